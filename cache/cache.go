@@ -11,6 +11,12 @@ type Service interface {
 	String() string
 }
 
+// Counter is implemented by caches which increment numbers atomically, nodes
+// sharing such a cache never receive the same number twice
+type Counter interface {
+	Incr([]byte, uint64) (uint64, error)
+}
+
 // New creates engine server
 func New(opts ...Option) Service {
 	o := newOptions(opts...)
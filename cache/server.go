@@ -38,6 +38,16 @@ func (v *server) Get(key []byte) ([]byte, error) {
 	return nil, nil
 }
 
+// Incr adds n to the counter of the key and returns its value, counters are
+// kept apart from the values of Set and Get
+func (v *server) Incr(key []byte, n uint64) (uint64, error) {
+	k := string(key[:])
+	if err := v.cache.Add(k, n, builtin.NoExpiration); err == nil {
+		return n, nil
+	}
+	return v.cache.IncrementUint64(k, n)
+}
+
 func (v *server) String() string {
 	return "Hyper::Cache"
 }
@@ -0,0 +1,214 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/vaniila/hyper/fault"
+	"github.com/vaniila/hyper/router"
+)
+
+// struct tags mapped to parameter sources
+var bindTags = []struct {
+	tag string
+	typ router.ParamType
+}{
+	{"query", router.ParamQuery},
+	{"body", router.ParamBody},
+	{"param", router.ParamParam},
+	{"header", router.ParamHeader},
+	{"cookie", router.ParamCookie},
+}
+
+var (
+	typeTime  = reflect.TypeOf(time.Time{})
+	typeBytes = reflect.TypeOf([]byte{})
+)
+
+// formats used to convert raw values into field kinds
+var bindFormats = map[reflect.Kind]int{
+	reflect.Bool:    router.Bool,
+	reflect.Int:     router.Int,
+	reflect.Int8:    router.I32,
+	reflect.Int16:   router.I32,
+	reflect.Int32:   router.I32,
+	reflect.Int64:   router.I64,
+	reflect.Uint:    router.U64,
+	reflect.Uint8:   router.U32,
+	reflect.Uint16:  router.U32,
+	reflect.Uint32:  router.U32,
+	reflect.Uint64:  router.U64,
+	reflect.Float32: router.F32,
+	reflect.Float64: router.F64,
+}
+
+// Bind fills struct fields tagged with query, body, param, header or cookie
+// from the declared route parameters
+func (v *Context) Bind(o interface{}) error {
+	rv := reflect.ValueOf(o)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fault.
+			New("Illegal Action").
+			SetStatus(http.StatusInternalServerError).
+			AddCause(
+				fault.
+					For(fault.Illegal).
+					SetResource("Bind"),
+			)
+	}
+	var causes []fault.Cause
+	v.bindStruct(rv.Elem(), &causes)
+	if len(causes) > 0 {
+		return fault.
+			New("Unprocessable Entity").
+			SetStatus(http.StatusUnprocessableEntity).
+			AddCause(causes...)
+	}
+	return nil
+}
+
+func (v *Context) bindStruct(rv reflect.Value, causes *[]fault.Cause) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			v.bindStruct(fv, causes)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		for _, bt := range bindTags {
+			tag, ok := field.Tag.Lookup(bt.tag)
+			if !ok {
+				continue
+			}
+			name, opts := tag, ""
+			if i := strings.Index(tag, ","); i >= 0 {
+				name, opts = tag[:i], tag[i+1:]
+			}
+			if name == "" {
+				name = field.Name
+			}
+			cause := fault.For(fault.Invalid).SetResource(bt.typ.String()).SetField(name)
+			value := v.MatchParameter(name, bt.typ)
+			switch {
+			case value == nil:
+				*causes = append(*causes, cause.SetCode(fault.UnregisteredField))
+			case !value.Has() && len(value.Val()) == 0:
+				if strings.Contains(opts, "required") {
+					*causes = append(*causes, cause.SetCode(fault.MissingField))
+				}
+			case !bindValue(fv, value):
+				*causes = append(*causes, cause)
+			}
+			break
+		}
+	}
+}
+
+func bindValue(fv reflect.Value, value router.Value) bool {
+	val, ok := value.(*Value)
+	if !ok {
+		return assign(fv, value.Val(), nil)
+	}
+	// parameters accepting multiple values fill slices element by element
+	if ft := fv.Type(); val.list != nil && ft.Kind() == reflect.Slice && ft != typeBytes {
+		sv := reflect.MakeSlice(ft, len(val.vals), len(val.vals))
+		for i, raw := range val.vals {
+			if !assign(sv.Index(i), raw, val.list[i]) {
+				return false
+			}
+		}
+		fv.Set(sv)
+		return true
+	}
+	return assign(fv, val.val, val.parsed)
+}
+
+func assign(fv reflect.Value, raw []byte, parsed interface{}) bool {
+	ft := fv.Type()
+	if ft.Kind() == reflect.Ptr {
+		elem := reflect.New(ft.Elem())
+		if !assign(elem.Elem(), raw, parsed) {
+			return false
+		}
+		fv.Set(elem)
+		return true
+	}
+	// use the value parsed by the declared format when it fits the field
+	if parsed != nil {
+		pv := reflect.ValueOf(parsed)
+		switch {
+		case pv.Type() == ft:
+			fv.Set(pv)
+			return true
+		case numeric(pv.Kind()) && numeric(ft.Kind()):
+			return convert(fv, pv)
+		}
+	}
+	switch {
+	case ft == typeBytes:
+		fv.SetBytes(raw)
+		return true
+	case ft == typeTime:
+		for _, typ := range []int{router.DateTimeRFC3339, router.DateTimeUnix, router.DateTimeRFC822} {
+			if t, ok := router.Val(typ, raw); ok {
+				fv.Set(reflect.ValueOf(t))
+				return true
+			}
+		}
+		return false
+	case ft.Kind() == reflect.String:
+		fv.SetString(string(raw))
+		return true
+	}
+	if typ, ok := bindFormats[ft.Kind()]; ok {
+		p, ok := router.Val(typ, raw)
+		if !ok {
+			return false
+		}
+		return convert(fv, reflect.ValueOf(p))
+	}
+	// structs, maps and slices are decoded from json input
+	ptr := reflect.New(ft)
+	if err := json.Unmarshal(raw, ptr.Interface()); err != nil {
+		return false
+	}
+	fv.Set(ptr.Elem())
+	return true
+}
+
+// convert sets the numeric value to the field, rejecting overflow and truncation
+func convert(fv, pv reflect.Value) bool {
+	cv := pv.Convert(fv.Type())
+	if cv.Convert(pv.Type()).Interface() != pv.Interface() || negative(cv) != negative(pv) {
+		return false
+	}
+	fv.Set(cv)
+	return true
+}
+
+func negative(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() < 0
+	}
+	return false
+}
+
+func numeric(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/vaniila/hyper/fault"
+	"github.com/vaniila/hyper/router"
+)
+
+type bindPage struct {
+	Size   int    `json:"size"`
+	Cursor string `json:"cursor"`
+}
+
+type bindSort struct {
+	Sort string `query:"sort"`
+}
+
+type bindTarget struct {
+	bindSort
+	I8     int8      `query:"i8"`
+	I64    int64     `query:"i64"`
+	U      uint      `query:"u"`
+	U16    uint16    `query:"u16"`
+	F32    *float32  `query:"f32"`
+	IDs    []int     `query:"ids"`
+	Names  []string  `query:"names"`
+	Page   bindPage  `query:"page"`
+	Pages  *bindPage `query:"pages"`
+	Name   string    `query:"name,required"`
+	hidden string    `query:"hidden"`
+}
+
+// bindFields lists the fields named by the causes of a bind error
+func bindFields(err error) string {
+	if err == nil {
+		return ""
+	}
+	f, ok := fault.Is(err)
+	if !ok {
+		return err.Error()
+	}
+	var list []string
+	for _, c := range f.Causes() {
+		list = append(list, c.Field()+":"+c.Code())
+	}
+	sort.Strings(list)
+	return strings.Join(list, " ")
+}
+
+func f32(f float32) *float32 { return &f }
+
+func TestBind(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		params []router.Param
+		want   bindTarget
+		causes string
+	}{
+		{"int in range", "i8=-128&i64=9007199254740993", []router.Param{param(router.ParamQuery, "i8", router.Int), param(router.ParamQuery, "i64", router.Int)}, bindTarget{I8: -128, I64: 9007199254740993}, ""},
+		{"int overflow", "i8=128", []router.Param{param(router.ParamQuery, "i8", router.Int)}, bindTarget{}, "i8:invalid"},
+		{"int overflow of text", "i8=300", []router.Param{param(router.ParamQuery, "i8", router.Text)}, bindTarget{}, "i8:invalid"},
+		{"unsigned", "u=7&u16=65535", []router.Param{param(router.ParamQuery, "u", router.Int), param(router.ParamQuery, "u16", router.Text)}, bindTarget{U: 7, U16: 65535}, ""},
+		{"unsigned negative", "u=-1", []router.Param{param(router.ParamQuery, "u", router.Int)}, bindTarget{}, "u:invalid"},
+		{"unsigned negative text", "u16=-5", []router.Param{param(router.ParamQuery, "u16", router.Text)}, bindTarget{}, "u16:invalid"},
+		{"unsigned overflow", "u16=65536", []router.Param{param(router.ParamQuery, "u16", router.Int)}, bindTarget{}, "u16:invalid"},
+		{"float truncation", "f32=0.1", []router.Param{param(router.ParamQuery, "f32", router.F64)}, bindTarget{}, "f32:invalid"},
+		{"float pointer", "f32=0.5", []router.Param{param(router.ParamQuery, "f32", router.Text)}, bindTarget{F32: f32(0.5)}, ""},
+		{"slice of multiple", "ids=1&ids=2&ids=3", []router.Param{param(router.ParamQuery, "ids", router.Int).multiple()}, bindTarget{IDs: []int{1, 2, 3}}, ""},
+		{"slice of strings", "names=a&names=&names=b", []router.Param{param(router.ParamQuery, "names", router.Text).multiple()}, bindTarget{Names: []string{"a", "b"}}, ""},
+		{"slice element invalid", "ids=1&ids=x", []router.Param{param(router.ParamQuery, "ids", router.Text).multiple()}, bindTarget{}, "ids:invalid"},
+		{"slice of single", "ids=[4,5]", []router.Param{param(router.ParamQuery, "ids", router.Text)}, bindTarget{IDs: []int{4, 5}}, ""},
+		{"nested struct", `page={"size":10,"cursor":"c"}`, []router.Param{param(router.ParamQuery, "page", router.Text)}, bindTarget{Page: bindPage{10, "c"}}, ""},
+		{"nested struct pointer", `pages={"size":2}`, []router.Param{param(router.ParamQuery, "pages", router.Text)}, bindTarget{Pages: &bindPage{Size: 2}}, ""},
+		{"nested struct invalid", `page={"size":"x"}`, []router.Param{param(router.ParamQuery, "page", router.Text)}, bindTarget{}, "page:invalid"},
+		{"embedded struct", "sort=name", []router.Param{param(router.ParamQuery, "sort", router.Text)}, bindTarget{bindSort: bindSort{"name"}}, ""},
+		{"unexported field", "hidden=x", []router.Param{param(router.ParamQuery, "hidden", router.Text)}, bindTarget{}, ""},
+		{"required", "", []router.Param{param(router.ParamQuery, "name", router.Text)}, bindTarget{}, "name:missing_field"},
+		{"default", "", []router.Param{param(router.ParamQuery, "name", router.Text).fallback("d")}, bindTarget{Name: "d"}, ""},
+		{"undeclared", "i64=1", nil, bindTarget{}, "i64:unregistered_field"},
+	}
+	for _, tt := range tests {
+		params := tt.params
+		// fields outside the case are declared so they are not reported
+		for _, name := range []string{"sort", "i8", "i64", "u", "u16", "f32", "ids", "names", "page", "pages", "name"} {
+			declared := false
+			for _, p := range params {
+				declared = declared || p.Config().Name() == name
+			}
+			if !declared && !(tt.name == "undeclared" && name == "i64") {
+				params = append(params, param(router.ParamQuery, name, router.Text))
+			}
+		}
+		if tt.want.Name == "" && tt.causes != "name:missing_field" {
+			tt.query += "&name=n"
+			tt.want.Name = "n"
+		}
+		c := resolveParams(tt.query, "", params...)
+		var got bindTarget
+		err := c.Bind(&got)
+		if causes := bindFields(err); causes != tt.causes {
+			t.Errorf("%s: causes %q, want %q", tt.name, causes, tt.causes)
+		}
+		if tt.causes == "" && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: bound %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBindTarget(t *testing.T) {
+	c := resolveParams("", "")
+	for _, o := range []interface{}{nil, bindTarget{}, new(int), (*bindTarget)(nil)} {
+		f, ok := fault.Is(c.Bind(o))
+		if !ok || f.Status() != 500 {
+			t.Errorf("bind of %T did not fail as illegal", o)
+		}
+	}
+}
@@ -42,9 +42,13 @@ type Context struct {
 	header               router.Header
 	aborted              bool
 	wrote                bool
+	pending              bool
 	statuscode           int
+	route                router.RouteConfig
+	validate             bool
 	params               []router.Param
 	values               []router.Value
+	payload              interface{}
 	warnings             []fault.Cause
 	uaparser             *uaparser.Parser
 	recover              error
@@ -86,7 +90,14 @@ func (v *Context) Req() *http.Request {
 	return v.req
 }
 
+// Res returns the response writer. While a status set through Status is
+// pending the writer is wrapped to send it along with the first write, the
+// wrapper forwards Flush, Hijack and Push and unwraps to the original writer
+// for http.ResponseController
 func (v *Context) Res() http.ResponseWriter {
+	if v.pending && !v.wrote {
+		return &tracker{v.res, v}
+	}
 	return v.res
 }
 
@@ -280,16 +291,78 @@ func (v *Context) Proto(i router.ProtoMessage) router.Context {
 	switch typ {
 	case typeJson:
 		out, _ = json.Marshal(i)
+		v.contentType(contentType(MIMEJson), false)
 	case typeProto:
 		out, _ = proto.Marshal(i)
+		v.contentType(MIMEProto, false)
 	}
 	return v.Write(out)
 }
 
+// Render serializes the entity with the encoder best matching the Accept
+// header, encoders unable to handle the entity are skipped
+func (v *Context) Render(o interface{}) router.Context {
+	v.conform(o)
+	encs := negotiate(v.req.Header.Get(headerAccept))
+	if len(encs) == 0 {
+		err := fault.
+			New("Not Acceptable").
+			SetStatus(http.StatusNotAcceptable).
+			AddCause(
+				fault.
+					For(fault.NotAllowed).
+					SetResource(router.ParamHeader.String()).
+					SetField(headerAccept),
+			)
+		panic(err)
+	}
+	for _, enc := range encs {
+		if b, err := enc.fn(o); err == nil {
+			v.res.Header().Add("Vary", headerAccept)
+			v.contentType(contentType(enc.mime), true)
+			return v.Write(b)
+		}
+	}
+	err := fault.
+		New("Problems serializing response").
+		SetStatus(http.StatusInternalServerError).
+		AddCause(
+			fault.
+				For(fault.Invalid).
+				SetResource("response"),
+		)
+	panic(err)
+}
+
+// conform validates a response entity against the route model of the
+// status when response validation is enabled
+func (v *Context) conform(o interface{}) {
+	if !v.validate || v.route == nil {
+		return
+	}
+	if model := v.route.Model(v.statuscode); model != nil && model.Schema() != nil {
+		if err := model.Schema().Validate(o); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// contentType sets the response media type unless the header has already
+// been sent, an existing value is only replaced when override is set
+func (v *Context) contentType(typ string, override bool) {
+	if v.wrote {
+		return
+	}
+	if override || v.res.Header().Get("Content-Type") == "" {
+		v.res.Header().Set("Content-Type", typ)
+	}
+}
+
 func (v *Context) Write(b []byte) router.Context {
 	if !v.IsAborted() {
 		if !v.wrote {
 			v.wrote = true
+			v.res.WriteHeader(v.statuscode)
 		}
 		v.res.Write(b)
 	}
@@ -300,9 +373,15 @@ func (v *Context) Error(e error) router.Context {
 	if e != nil {
 		ext.Error.Set(v.span, true)
 		if f, ok := fault.Is(e); ok {
-			v.Status(f.Status())
+			// the fault status replaces one which has not been sent yet
+			if !v.IsAborted() && !v.wrote {
+				v.statuscode = f.Status()
+				v.pending = true
+			}
+			v.contentType(contentType(MIMEJson), true)
 			v.Write(f.Json())
 		} else {
+			v.contentType("text/plain"+charsetUTF8, true)
 			v.Write([]byte(e.Error()))
 		}
 	}
@@ -310,6 +389,7 @@ func (v *Context) Error(e error) router.Context {
 }
 
 func (v *Context) Json(o interface{}) router.Context {
+	v.conform(o)
 	switch b, e := json.Marshal(o); {
 	case e != nil:
 		err := fault.
@@ -322,20 +402,31 @@ func (v *Context) Json(o interface{}) router.Context {
 			)
 		panic(err)
 	default:
+		v.contentType(contentType(MIMEJson), false)
 		v.Write(b)
 	}
 	return v
 }
 
+// Status sets the response status code, the header is sent along with the
+// first write so content type and other headers may still be set afterwards.
+// As when the header was sent at once, the first status set is kept
 func (v *Context) Status(code int) router.Context {
-	if !v.IsAborted() && !v.wrote {
-		v.res.WriteHeader(code)
+	if !v.IsAborted() && !v.wrote && !v.pending {
 		v.statuscode = code
-		v.wrote = true
+		v.pending = true
 	}
 	return v
 }
 
+// flush sends a status set without any body being written
+func (v *Context) flush() {
+	if v.pending && !v.wrote {
+		v.wrote = true
+		v.res.WriteHeader(v.statuscode)
+	}
+}
+
 func (v *Context) Child() router.Context {
 	child := new(Context)
 	*child = *v
@@ -22,6 +22,7 @@ func New(opts ...Option) Service {
 		websocket:  o.Websocket,
 		logger:     o.Logger,
 		traceid:    o.TraceID,
+		opts:       o,
 	}
 	return s
 }
@@ -0,0 +1,329 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+var (
+	errMsgpackShort = errors.New("msgpack: unexpected end of data")
+	errMsgpackDepth = errors.New("msgpack: exceeded max depth")
+)
+
+// msgpackMaxDepth bounds the nesting of arrays and objects like encoding/json
+// does, deeper documents would exhaust the stack
+const msgpackMaxDepth = 10000
+
+// msgpack decodes msgpack documents into the same generic values as decodeJSON,
+// numbers are kept as json.Number so both formats validate identically
+type msgpack struct {
+	b   []byte
+	off int
+}
+
+func decodeMsgpack(b []byte) (interface{}, error) {
+	d := &msgpack{b: b}
+	o, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.off != len(d.b) {
+		return nil, errors.New("msgpack: trailing data")
+	}
+	return o, nil
+}
+
+func (v *msgpack) next(n int) ([]byte, error) {
+	if n < 0 || v.off+n > len(v.b) {
+		return nil, errMsgpackShort
+	}
+	b := v.b[v.off : v.off+n]
+	v.off += n
+	return b, nil
+}
+
+func (v *msgpack) uint(n int) (uint64, error) {
+	b, err := v.next(n)
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (v *msgpack) int(n int) (json.Number, error) {
+	u, err := v.uint(n)
+	if err != nil {
+		return "", err
+	}
+	var i int64
+	switch n {
+	case 1:
+		i = int64(int8(u))
+	case 2:
+		i = int64(int16(u))
+	case 4:
+		i = int64(int32(u))
+	default:
+		i = int64(u)
+	}
+	return json.Number(strconv.FormatInt(i, 10)), nil
+}
+
+func (v *msgpack) str(n int) (string, error) {
+	b, err := v.next(n)
+	return string(b), err
+}
+
+// fits rejects lengths the remaining data cannot hold before allocating,
+// every element takes at least one byte
+func (v *msgpack) fits(n int) error {
+	if n < 0 || n > len(v.b)-v.off {
+		return errMsgpackShort
+	}
+	return nil
+}
+
+// nest checks the length and the depth of an array or object
+func (v *msgpack) nest(n, depth int) error {
+	if depth > msgpackMaxDepth {
+		return errMsgpackDepth
+	}
+	return v.fits(n)
+}
+
+func (v *msgpack) array(n, depth int) (interface{}, error) {
+	if err := v.nest(n, depth); err != nil {
+		return nil, err
+	}
+	a := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		o, err := v.value(depth)
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, o)
+	}
+	return a, nil
+}
+
+func (v *msgpack) object(n, depth int) (interface{}, error) {
+	if err := v.nest(n, depth); err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := v.value(depth)
+		if err != nil {
+			return nil, err
+		}
+		o, err := v.value(depth)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(k)] = o
+	}
+	return m, nil
+}
+
+func (v *msgpack) length(n int) (int, error) {
+	u, err := v.uint(n)
+	return int(u), err
+}
+
+// value decodes the next value, depth is the number of arrays and objects
+// enclosing it
+func (v *msgpack) value(depth int) (interface{}, error) {
+	b, err := v.next(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return json.Number(strconv.Itoa(int(c))), nil
+	case c >= 0xe0:
+		return json.Number(strconv.Itoa(int(int8(c)))), nil
+	case c >= 0x80 && c <= 0x8f:
+		return v.object(int(c&0x0f), depth+1)
+	case c >= 0x90 && c <= 0x9f:
+		return v.array(int(c&0x0f), depth+1)
+	case c >= 0xa0 && c <= 0xbf:
+		return v.str(int(c & 0x1f))
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := v.length(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return v.str(n)
+	case 0xd9, 0xda, 0xdb:
+		n, err := v.length(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return v.str(n)
+	case 0xca:
+		u, err := v.uint(4)
+		if err != nil {
+			return nil, err
+		}
+		return json.Number(strconv.FormatFloat(float64(math.Float32frombits(uint32(u))), 'g', -1, 32)), nil
+	case 0xcb:
+		u, err := v.uint(8)
+		if err != nil {
+			return nil, err
+		}
+		return json.Number(strconv.FormatFloat(math.Float64frombits(u), 'g', -1, 64)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := v.uint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return json.Number(strconv.FormatUint(u, 10)), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		return v.int(1 << (c - 0xd0))
+	case 0xdc, 0xdd:
+		n, err := v.length(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return v.array(n, depth+1)
+	case 0xde, 0xdf:
+		n, err := v.length(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return v.object(n, depth+1)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		// extension types carry no generic meaning and are skipped
+		_, err := v.next(1 + 1<<(c-0xd4))
+		return nil, err
+	case 0xc7, 0xc8, 0xc9:
+		n, err := v.length(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		_, err = v.next(1 + n)
+		return nil, err
+	}
+	return nil, fmt.Errorf("msgpack: unknown type 0x%x", c)
+}
+
+// encodeMsgpack serializes through the entity's JSON representation so struct
+// tags and custom marshalers apply to both formats alike
+func encodeMsgpack(o interface{}) ([]byte, error) {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	generic, err := decodeJSON(b)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	if err := writeMsgpack(buf, generic); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeMsgpackHeader(buf *bytes.Buffer, n int, fix, base byte, fixmax int) {
+	switch {
+	case n <= fixmax:
+		buf.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(base)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(base + 1)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+func writeMsgpack(buf *bytes.Buffer, o interface{}) error {
+	switch v := o.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			if i >= -32 && i <= 127 {
+				buf.WriteByte(byte(int8(i)))
+				return nil
+			}
+			buf.WriteByte(0xd3)
+			binary.Write(buf, binary.BigEndian, i)
+			return nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(0xcb)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+	case string:
+		switch n := len(v); {
+		case n <= 31:
+			buf.WriteByte(0xa0 | byte(n))
+		case n <= math.MaxUint8:
+			buf.WriteByte(0xd9)
+			buf.WriteByte(byte(n))
+		case n <= math.MaxUint16:
+			buf.WriteByte(0xda)
+			binary.Write(buf, binary.BigEndian, uint16(n))
+		default:
+			buf.WriteByte(0xdb)
+			binary.Write(buf, binary.BigEndian, uint32(n))
+		}
+		buf.WriteString(v)
+	case []interface{}:
+		writeMsgpackHeader(buf, len(v), 0x90, 0xdc, 15)
+		for _, item := range v {
+			if err := writeMsgpack(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		writeMsgpackHeader(buf, len(v), 0x80, 0xde, 15)
+		for _, k := range keys {
+			writeMsgpack(buf, k)
+			if err := writeMsgpack(buf, v[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported type %T", o)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeMsgpack(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		want interface{}
+	}{
+		{"nil", []byte{0xc0}, nil},
+		{"false", []byte{0xc2}, false},
+		{"true", []byte{0xc3}, true},
+		{"positive fixint", []byte{0x7f}, json.Number("127")},
+		{"negative fixint", []byte{0xff}, json.Number("-1")},
+		{"uint8", []byte{0xcc, 0xff}, json.Number("255")},
+		{"uint16", []byte{0xcd, 0x01, 0x00}, json.Number("256")},
+		{"int8", []byte{0xd0, 0x80}, json.Number("-128")},
+		{"int32", []byte{0xd2, 0xff, 0xff, 0xff, 0xfe}, json.Number("-2")},
+		{"float64", []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}, json.Number("1.5")},
+		{"fixstr", []byte{0xa2, 'h', 'i'}, "hi"},
+		{"str8", []byte{0xd9, 0x02, 'h', 'i'}, "hi"},
+		{"bin8", []byte{0xc4, 0x01, 'x'}, "x"},
+		{"fixarray", []byte{0x92, 0x01, 0xa1, 'a'}, []interface{}{json.Number("1"), "a"}},
+		{"array16", []byte{0xdc, 0x00, 0x01, 0xc3}, []interface{}{true}},
+		{"fixmap", []byte{0x81, 0xa1, 'k', 0x02}, map[string]interface{}{"k": json.Number("2")}},
+		{"map16", []byte{0xde, 0x00, 0x01, 0xa1, 'k', 0xc0}, map[string]interface{}{"k": nil}},
+		{"fixext skipped", []byte{0xd4, 0x01, 0x00}, nil},
+	}
+	for _, tt := range tests {
+		got, err := decodeMsgpack(tt.in)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeMsgpackMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+	}{
+		{"empty", []byte{}},
+		{"truncated uint16", []byte{0xcd, 0x01}},
+		{"truncated float64", []byte{0xcb, 0x3f, 0xf8}},
+		{"truncated fixstr", []byte{0xa3, 'h', 'i'}},
+		{"truncated str8 header", []byte{0xd9}},
+		{"truncated array16 header", []byte{0xdc, 0x00}},
+		{"truncated map32 header", []byte{0xdf, 0x00, 0x00}},
+		{"missing array element", []byte{0x92, 0x01}},
+		{"missing map value", []byte{0x81, 0xa1, 'k'}},
+		{"oversized array32", []byte{0xdd, 0xff, 0xff, 0xff, 0xff}},
+		{"oversized map32", []byte{0xdf, 0x7f, 0xff, 0xff, 0xff, 0xc0}},
+		{"oversized str32", []byte{0xdb, 0xff, 0xff, 0xff, 0xff, 'x'}},
+		{"oversized ext32", []byte{0xc9, 0xff, 0xff, 0xff, 0xff, 0x01}},
+		{"unknown type", []byte{0xc1}},
+		{"trailing data", []byte{0xc0, 0xc0}},
+	}
+	for _, tt := range tests {
+		if got, err := decodeMsgpack(tt.in); err == nil {
+			t.Errorf("%s: expected error, got %#v", tt.name, got)
+		}
+	}
+}
+
+func TestDecodeMsgpackDepth(t *testing.T) {
+	nested := func(depth int, open byte) []byte {
+		b := bytes.Repeat([]byte{open}, depth)
+		if open == 0x81 {
+			b = make([]byte, 0, depth*2)
+			for i := 0; i < depth; i++ {
+				b = append(b, open, 0xa0)
+			}
+		}
+		return append(b, 0xc0)
+	}
+	tests := []struct {
+		name string
+		in   []byte
+		err  error
+	}{
+		{"arrays at the limit", nested(msgpackMaxDepth, 0x91), nil},
+		{"arrays past the limit", nested(msgpackMaxDepth+1, 0x91), errMsgpackDepth},
+		{"objects past the limit", nested(msgpackMaxDepth+1, 0x81), errMsgpackDepth},
+		{"huge nesting", nested(30<<20, 0x91), errMsgpackDepth},
+	}
+	for _, tt := range tests {
+		if _, err := decodeMsgpack(tt.in); err != tt.err {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestEncodeMsgpackRoundTrip(t *testing.T) {
+	tests := []interface{}{
+		nil,
+		true,
+		"text",
+		42,
+		-1000,
+		2.25,
+		[]interface{}{"a", 1, nil},
+		map[string]interface{}{"a": []interface{}{true}, "b": "c"},
+	}
+	for _, in := range tests {
+		b, err := encodeMsgpack(in)
+		if err != nil {
+			t.Errorf("%v: encode failed %v", in, err)
+			continue
+		}
+		got, err := decodeMsgpack(b)
+		if err != nil {
+			t.Errorf("%v: decode failed %v", in, err)
+			continue
+		}
+		j, _ := json.Marshal(in)
+		want, _ := decodeJSON(j)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: got %#v, want %#v", in, got, want)
+		}
+	}
+}
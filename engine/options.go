@@ -11,6 +11,7 @@ import (
 	"github.com/vaniila/hyper/logger"
 	"github.com/vaniila/hyper/message"
 	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/swagger"
 	"github.com/vaniila/hyper/websocket"
 )
 
@@ -56,6 +57,19 @@ type Options struct {
 	// EnableCompression to enable gzip compression
 	EnableCompression bool
 
+	// EnableSwagger to serve the OpenAPI document and swagger ui
+	EnableSwagger bool
+
+	// ValidateResponses checks entities rendered by Json and Render against
+	// the route model of the response status
+	ValidateResponses bool
+
+	// SwaggerPath is the path the OpenAPI document and swagger ui are served from
+	SwaggerPath string
+
+	// Swagger document options
+	Swagger []swagger.Option
+
 	// EnableCORS to attach cors handler to http server
 	EnableCORS bool
 
@@ -109,10 +123,11 @@ func newID() string {
 
 func newOptions(opts ...Option) Options {
 	opt := Options{
-		ID:       newID(),
-		Addr:     ":0",
-		Protocol: HTTP,
-		TraceID:  newID,
+		ID:          newID(),
+		Addr:        ":0",
+		Protocol:    HTTP,
+		TraceID:     newID,
+		SwaggerPath: "/swagger",
 	}
 	for _, o := range opts {
 		o(&opt)
@@ -227,6 +242,34 @@ func EnableCompression(b bool) Option {
 	}
 }
 
+// ValidateResponses to check rendered entities against route models
+func ValidateResponses(b bool) Option {
+	return func(o *Options) {
+		o.ValidateResponses = b
+	}
+}
+
+// EnableSwagger to serve the OpenAPI document and swagger ui
+func EnableSwagger(b bool) Option {
+	return func(o *Options) {
+		o.EnableSwagger = b
+	}
+}
+
+// SwaggerPath to set the path of the OpenAPI document and swagger ui
+func SwaggerPath(s string) Option {
+	return func(o *Options) {
+		o.SwaggerPath = s
+	}
+}
+
+// Swagger to set OpenAPI document options
+func Swagger(opts ...swagger.Option) Option {
+	return func(o *Options) {
+		o.Swagger = append(o.Swagger, opts...)
+	}
+}
+
 // EnableCORS to attach cors handler to http server
 func EnableCORS(b bool) Option {
 	return func(o *Options) {
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/vaniila/hyper/router"
+)
+
+// decoders for structured request bodies keyed by media type
+var decoders = map[string]func([]byte) (interface{}, error){
+	"application/json":      decodeJSON,
+	"application/msgpack":   decodeMsgpack,
+	"application/x-msgpack": decodeMsgpack,
+}
+
+func decodeJSON(b []byte) (interface{}, error) {
+	var o interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// decoderFor returns the body decoder for the request content type
+func decoderFor(contentType string) func([]byte) (interface{}, error) {
+	typ, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	if decoder, ok := decoders[typ]; ok {
+		return decoder
+	}
+	if strings.HasSuffix(typ, "+json") {
+		return decodeJSON
+	}
+	return nil
+}
+
+// hasBody reports whether any parameter, including one of choices, is read
+// from the request body
+func hasBody(params []router.Param) bool {
+	for _, param := range params {
+		conf := param.Config()
+		if conf.Type() == router.ParamBody || hasBody(conf.OneOf()) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupAll resolves a dotted name against a decoded body, arrays are
+// expanded into their elements for parameters accepting multiple values
+func lookupAll(o interface{}, name string, multiple bool) ([][]byte, bool) {
+	if multiple {
+		if a, ok := resolve(o, name).([]interface{}); ok {
+			var vs [][]byte
+			for _, item := range a {
+				if b, ok := encode(item); ok {
+					vs = append(vs, b)
+				}
+			}
+			return vs, len(vs) > 0
+		}
+	}
+	b, ok := encode(resolve(o, name))
+	if !ok {
+		return nil, false
+	}
+	return [][]byte{b}, true
+}
+
+func resolve(o interface{}, name string) interface{} {
+	for _, key := range strings.Split(name, ".") {
+		switch v := o.(type) {
+		case map[string]interface{}:
+			val, ok := v[key]
+			if !ok {
+				return nil
+			}
+			o = val
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			o = v[i]
+		default:
+			return nil
+		}
+	}
+	return o
+}
+
+func encode(o interface{}) ([]byte, bool) {
+	switch v := o.(type) {
+	case nil:
+		return nil, false
+	case string:
+		return []byte(v), true
+	case json.Number:
+		return []byte(v.String()), true
+	case bool:
+		return []byte(strconv.FormatBool(v)), true
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
@@ -0,0 +1,169 @@
+package engine
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/vaniila/hyper/router"
+)
+
+// MIME types of the built-in response encoders
+const (
+	MIMEJson     = "application/json"
+	MIMEProto    = "application/x-protobuf"
+	MIMEMsgpack  = "application/msgpack"
+	MIMEXml      = "application/xml"
+	charsetUTF8  = "; charset=utf-8"
+	headerAccept = "Accept"
+)
+
+type encoder struct {
+	mime string
+	fn   router.Encoder
+}
+
+// encoders in order of server preference, guarded by the mutex as
+// applications may register their own at any time
+var (
+	encoders = []encoder{
+		{MIMEJson, encodeJSON},
+		{MIMEProto, encodeProto},
+		{MIMEMsgpack, encodeMsgpack},
+		{MIMEXml, xml.Marshal},
+	}
+	encodersMu sync.RWMutex
+)
+
+var errNotProto = errors.New("entity is not a protobuf message")
+
+// RegisterEncoder adds a response encoder for a media type, replacing the
+// existing one if it has already been registered
+func RegisterEncoder(mediaType string, fn router.Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	for i, enc := range encoders {
+		if enc.mime == mediaType {
+			encoders[i].fn = fn
+			return
+		}
+	}
+	encoders = append(encoders, encoder{mediaType, fn})
+}
+
+func encodeJSON(o interface{}) ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func encodeProto(o interface{}) ([]byte, error) {
+	if m, ok := o.(proto.Message); ok {
+		return proto.Marshal(m)
+	}
+	return nil, errNotProto
+}
+
+// contentType appends the charset to textual media types
+func contentType(mediaType string) string {
+	if mediaType == MIMEJson || mediaType == MIMEXml || strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return mediaType + charsetUTF8
+	}
+	return mediaType
+}
+
+type accepted struct {
+	typ, sub string
+	q        float64
+}
+
+// parseAccept splits an Accept header into media ranges
+func parseAccept(header string) []accepted {
+	var list []accepted
+	for _, part := range strings.Split(header, ",") {
+		typ, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		a := accepted{q: 1}
+		if q, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(q, 64); err == nil {
+				a.q = f
+			}
+		}
+		if i := strings.IndexByte(typ, '/'); i > 0 {
+			a.typ, a.sub = typ[:i], typ[i+1:]
+		} else if typ == "*" {
+			a.typ, a.sub = "*", "*"
+		} else {
+			continue
+		}
+		list = append(list, a)
+	}
+	return list
+}
+
+func (a accepted) specificity() int {
+	switch {
+	case a.typ == "*":
+		return 0
+	case a.sub == "*":
+		return 1
+	}
+	return 2
+}
+
+func (a accepted) match(mediaType string) bool {
+	i := strings.IndexByte(mediaType, '/')
+	if i < 0 {
+		return false
+	}
+	typ, sub := mediaType[:i], mediaType[i+1:]
+	return (a.typ == "*" || a.typ == typ) && (a.sub == "*" || a.sub == sub)
+}
+
+// negotiate returns the registered encoders acceptable to the client in order
+// of preference. Each encoder takes the quality of the most specific range it
+// matches, ties are broken by specificity then registration order. An absent
+// Accept header accepts everything.
+func negotiate(header string) []encoder {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	if strings.TrimSpace(header) == "" {
+		return append([]encoder(nil), encoders...)
+	}
+	type candidate struct {
+		encoder
+		best accepted
+	}
+	var (
+		ranges = parseAccept(header)
+		list   []candidate
+	)
+	for _, enc := range encoders {
+		best := accepted{q: -1}
+		for _, a := range ranges {
+			if a.match(enc.mime) && (best.q < 0 || a.specificity() > best.specificity()) {
+				best = a
+			}
+		}
+		if best.q > 0 {
+			list = append(list, candidate{enc, best})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].best.q != list[j].best.q {
+			return list[i].best.q > list[j].best.q
+		}
+		return list[i].best.specificity() > list[j].best.specificity()
+	})
+	encs := make([]encoder, len(list))
+	for i, c := range list {
+		encs[i] = c.encoder
+	}
+	return encs
+}
@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	all := []string{MIMEJson, MIMEProto, MIMEMsgpack, MIMEXml}
+	tests := []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{"absent", "", all},
+		{"blank", "  ", all},
+		{"any", "*/*", all},
+		{"single", "application/xml", []string{MIMEXml}},
+		{"unsupported", "text/html", []string{}},
+		{"malformed", ";;,", []string{}},
+		{"quality order", "application/json;q=0.5, application/msgpack", []string{MIMEMsgpack, MIMEJson}},
+		{"ties keep server order", "application/xml, application/json", []string{MIMEJson, MIMEXml}},
+		{"subtype range", "application/*", all},
+		{"specific range wins", "*/*;q=0.1, application/xml", []string{MIMEXml, MIMEJson, MIMEProto, MIMEMsgpack}},
+		{"zero quality excludes", "*/*, application/json;q=0", []string{MIMEProto, MIMEMsgpack, MIMEXml}},
+		{"zero quality range", "application/*;q=0, application/xml", []string{MIMEXml}},
+		{"invalid quality ignored", "application/json;q=abc", []string{MIMEJson}},
+		{"bare wildcard", "*", all},
+	}
+	for _, tt := range tests {
+		got := []string{}
+		for _, enc := range negotiate(tt.header) {
+			got = append(got, enc.mime)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: negotiate(%q) = %v, want %v", tt.name, tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestContentType(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{MIMEJson, MIMEJson + charsetUTF8},
+		{MIMEXml, MIMEXml + charsetUTF8},
+		{"text/plain", "text/plain" + charsetUTF8},
+		{"application/hal+json", "application/hal+json" + charsetUTF8},
+		{"application/atom+xml", "application/atom+xml" + charsetUTF8},
+		{MIMEProto, MIMEProto},
+		{MIMEMsgpack, MIMEMsgpack},
+	}
+	for _, tt := range tests {
+		if got := contentType(tt.in); got != tt.want {
+			t.Errorf("contentType(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vaniila/hyper/fault"
+	"github.com/vaniila/hyper/router"
+)
+
+const (
+	mimeOctetStream = "application/octet-stream"
+	mimeEventStream = "text/event-stream"
+)
+
+// tracker keeps the context status and write state in sync for helpers
+// writing through the standard library
+type tracker struct {
+	http.ResponseWriter
+	context *Context
+}
+
+// WriteHeader sends the status, a status set through Status takes precedence
+func (v *tracker) WriteHeader(code int) {
+	if v.context.pending {
+		code = v.context.statuscode
+	}
+	if !v.context.wrote {
+		v.context.statuscode = code
+		v.context.wrote = true
+		v.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (v *tracker) Write(b []byte) (int, error) {
+	if !v.context.wrote {
+		v.WriteHeader(v.context.statuscode)
+	}
+	return v.ResponseWriter.Write(b)
+}
+
+// Flush sends a pending status before flushing buffered data
+func (v *tracker) Flush() {
+	if !v.context.wrote {
+		v.WriteHeader(v.context.statuscode)
+	}
+	if flusher, ok := v.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hands the connection over, nothing is written to the response
+// afterwards
+func (v *tracker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := v.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("engine: response does not support hijacking")
+	}
+	v.context.wrote = true
+	return hijacker.Hijack()
+}
+
+// Push initiates an HTTP/2 server push when the response supports it
+func (v *tracker) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := v.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap returns the original response writer
+func (v *tracker) Unwrap() http.ResponseWriter {
+	return v.ResponseWriter
+}
+
+func (v *Context) writable() bool {
+	return !v.IsAborted() && !v.wrote
+}
+
+// Redirect replies with a redirection to the url
+func (v *Context) Redirect(code int, url string) router.Context {
+	if code < http.StatusMultipleChoices || code > http.StatusPermanentRedirect {
+		err := fault.
+			New("Illegal Action").
+			SetStatus(http.StatusInternalServerError).
+			AddCause(
+				fault.
+					For(fault.Illegal).
+					SetResource("Redirect"),
+			)
+		panic(err)
+	}
+	if v.writable() {
+		http.Redirect(&tracker{v.res, v}, v.req, url, code)
+	}
+	return v
+}
+
+// ServeFile replies with the content of a local file, honoring Range and
+// conditional request headers
+func (v *Context) ServeFile(path string) router.Context {
+	if !v.writable() {
+		return v
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		panic(fileNotFound())
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil || stat.IsDir() {
+		panic(fileNotFound())
+	}
+	http.ServeContent(&tracker{v.res, v}, v.req, stat.Name(), stat.ModTime(), f)
+	return v
+}
+
+// Attachment replies with the reader content as a download, seekable readers
+// support Range requests and files also the If-Modified-Since header
+func (v *Context) Attachment(name string, r io.Reader) router.Context {
+	if !v.writable() {
+		return v
+	}
+	v.res.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": filepath.Base(name),
+	}))
+	if rs, ok := r.(io.ReadSeeker); ok {
+		var modtime time.Time
+		if f, ok := r.(*os.File); ok {
+			if stat, err := f.Stat(); err == nil {
+				modtime = stat.ModTime()
+			}
+		}
+		http.ServeContent(&tracker{v.res, v}, v.req, name, modtime, rs)
+		return v
+	}
+	if typ := mime.TypeByExtension(filepath.Ext(name)); typ != "" {
+		v.contentType(typ, false)
+	}
+	return v.Stream(r)
+}
+
+// Stream copies the reader to the response, flushing after every chunk
+func (v *Context) Stream(r io.Reader) router.Context {
+	if v.IsAborted() {
+		return v
+	}
+	v.contentType(mimeOctetStream, false)
+	var (
+		buf        = make([]byte, 32*1024)
+		flusher, _ = v.res.(http.Flusher)
+	)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			v.Write(buf[:n])
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil || v.req.Context().Err() != nil {
+			break
+		}
+	}
+	return v
+}
+
+// SSE switches the response to a server-sent event stream
+func (v *Context) SSE() router.EventStream {
+	v.contentType(mimeEventStream, true)
+	if !v.wrote {
+		v.res.Header().Set("Cache-Control", "no-cache")
+		v.res.Header().Set("Connection", "keep-alive")
+		v.res.Header().Set("X-Accel-Buffering", "no")
+	}
+	stream := &eventStream{context: v}
+	stream.write(nil)
+	return stream
+}
+
+type eventStream struct {
+	sync.Mutex
+	context *Context
+}
+
+func (v *eventStream) write(b []byte) error {
+	v.Lock()
+	defer v.Unlock()
+	if err := v.context.req.Context().Err(); err != nil {
+		return err
+	}
+	if v.context.IsAborted() {
+		return io.ErrClosedPipe
+	}
+	w := &tracker{v.context.res, v.context}
+	if !v.context.wrote {
+		w.WriteHeader(v.context.statuscode)
+	}
+	if len(b) > 0 {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := v.context.res.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// Send writes an event to the stream
+func (v *eventStream) Send(e router.Event) error {
+	var buf bytes.Buffer
+	if e.ID != "" {
+		buf.WriteString("id: " + singleLine(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		buf.WriteString("event: " + singleLine(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		buf.WriteString("retry: " + strconv.FormatInt(int64(e.Retry/time.Millisecond), 10) + "\n")
+	}
+	data := strings.Replace(string(e.Data), "\r\n", "\n", -1)
+	for _, line := range strings.Split(data, "\n") {
+		buf.WriteString("data: " + line + "\n")
+	}
+	buf.WriteByte('\n')
+	return v.write(buf.Bytes())
+}
+
+// Comment writes a comment line, commonly used to keep the stream alive
+func (v *eventStream) Comment(s string) error {
+	return v.write([]byte(": " + singleLine(s) + "\n\n"))
+}
+
+// Done is closed once the client goes away
+func (v *eventStream) Done() <-chan struct{} {
+	return v.context.req.Context().Done()
+}
+
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+func fileNotFound() error {
+	return fault.
+		New("Resource Not Found").
+		SetStatus(http.StatusNotFound).
+		AddCause(
+			fault.
+				For(fault.Missing).
+				SetResource("file"),
+		)
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/vaniila/hyper/fault"
+)
+
+func newResponseContext() (*Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	return &Context{
+		req:        httptest.NewRequest("GET", "/", nil),
+		res:        w,
+		statuscode: http.StatusOK,
+		span:       opentracing.NoopTracer{}.StartSpan("test"),
+	}, w
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		fn     func(c *Context)
+		code   int
+		header string
+	}{
+		{"default", func(c *Context) { c.Write([]byte("x")) }, http.StatusOK, ""},
+		{"first status wins", func(c *Context) { c.Status(http.StatusNotFound).Status(http.StatusConflict).Write([]byte("x")) }, http.StatusNotFound, ""},
+		{"headers after status", func(c *Context) {
+			c.Status(http.StatusCreated)
+			c.Res().Header().Set("X-Test", "1")
+			c.Json(map[string]int{"a": 1})
+		}, http.StatusCreated, "1"},
+		{"through the writer", func(c *Context) { c.Status(http.StatusAccepted).Res().Write([]byte("x")) }, http.StatusAccepted, ""},
+		{"writer status loses to status", func(c *Context) { c.Status(http.StatusAccepted).Res().WriteHeader(http.StatusTeapot) }, http.StatusAccepted, ""},
+		{"fault replaces a pending status", func(c *Context) {
+			c.Status(http.StatusCreated).Error(fault.New("Conflict").SetStatus(http.StatusConflict))
+		}, http.StatusConflict, ""},
+		{"fault after the header", func(c *Context) {
+			c.Status(http.StatusCreated).Write([]byte("x")).Error(fault.New("Conflict").SetStatus(http.StatusConflict))
+		}, http.StatusCreated, ""},
+		{"status without body", func(c *Context) { c.Status(http.StatusNoContent).Res().(http.Flusher).Flush() }, http.StatusNoContent, ""},
+	}
+	for _, tt := range tests {
+		c, w := newResponseContext()
+		tt.fn(c)
+		if w.Code != tt.code {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.code)
+		}
+		if got := w.Header().Get("X-Test"); got != tt.header {
+			t.Errorf("%s: header %q, want %q", tt.name, got, tt.header)
+		}
+	}
+}
+
+func TestResWriter(t *testing.T) {
+	c, w := newResponseContext()
+	if _, ok := c.Res().(*httptest.ResponseRecorder); !ok {
+		t.Error("writer is wrapped without a pending status")
+	}
+	c.Status(http.StatusCreated)
+	res := c.Res()
+	if _, ok := res.(http.Flusher); !ok {
+		t.Error("wrapped writer is not a flusher")
+	}
+	pusher, ok := res.(http.Pusher)
+	if !ok {
+		t.Fatal("wrapped writer is not a pusher")
+	}
+	if err := pusher.Push("/a", nil); err != http.ErrNotSupported {
+		t.Errorf("push returned %v, want %v", err, http.ErrNotSupported)
+	}
+	if u, ok := res.(interface{ Unwrap() http.ResponseWriter }); !ok || u.Unwrap() != w {
+		t.Error("wrapped writer does not unwrap to the response")
+	}
+	if err := http.NewResponseController(res).Flush(); err != nil {
+		t.Errorf("response controller flush: %v", err)
+	}
+	if w.Code != http.StatusCreated || !w.Flushed {
+		t.Errorf("flush sent status %d, flushed %v", w.Code, w.Flushed)
+	}
+	if _, ok := c.Res().(*httptest.ResponseRecorder); !ok {
+		t.Error("writer is still wrapped after the header was sent")
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
@@ -18,6 +21,7 @@ import (
 	"github.com/vaniila/hyper/logger"
 	"github.com/vaniila/hyper/message"
 	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/swagger"
 	"github.com/vaniila/hyper/websocket"
 
 	"github.com/opentracing/opentracing-go"
@@ -47,6 +51,77 @@ type server struct {
 	opts       Options
 }
 
+// validate parses a single value with the parameter format, custom check
+// and constraints, returning the fault code of the first failed check
+func validate(conf router.ParamConfig, b []byte) (interface{}, string) {
+	parsed, ok := router.Val(conf.Format(), b)
+	if custom := conf.Custom(); ok && custom != nil {
+		ok = custom(b)
+	}
+	if !ok {
+		return parsed, fault.Invalid
+	}
+	return parsed, constrain(conf, b, parsed)
+}
+
+func constrain(conf router.ParamConfig, b []byte, parsed interface{}) string {
+	if f, ok := number(parsed); ok {
+		if min := conf.Min(); min != nil && f < *min {
+			return fault.TooSmall
+		}
+		if max := conf.Max(); max != nil && f > *max {
+			return fault.TooLarge
+		}
+	}
+	l := utf8.RuneCount(b)
+	if min := conf.MinLength(); min != nil && l < *min {
+		return fault.TooShort
+	}
+	if max := conf.MaxLength(); max != nil && l > *max {
+		return fault.TooLong
+	}
+	if pattern := conf.Pattern(); pattern != nil && !pattern.Match(b) {
+		return fault.Mismatch
+	}
+	if enum := conf.Enum(); len(enum) > 0 {
+		for _, e := range enum {
+			if e == string(b) {
+				return ""
+			}
+		}
+		return fault.NotAllowed
+	}
+	return ""
+}
+
+func number(o interface{}) (float64, bool) {
+	switch v := o.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toBytes(vs []string) [][]byte {
+	bs := make([][]byte, len(vs))
+	for i, s := range vs {
+		bs[i] = []byte(s)
+	}
+	return bs
+}
+
 func (v *server) handleParameters(c *Context, route router.RouteConfig, params []router.Param) {
 	r := c.Req()
 	for _, pa := range params {
@@ -68,11 +143,19 @@ func (v *server) handleParameters(c *Context, route router.RouteConfig, params [
 					data.val = b.Bytes()
 					data.has = l > 0 && e == nil
 				}
+			} else if c.payload != nil {
+				if vs, ok := lookupAll(c.payload, conf.Name(), conf.Multiple()); ok {
+					data.val = vs[0]
+					data.vals = vs
+					data.has = true
+				}
 			} else if vs := r.Form[conf.Name()]; len(vs) > 0 {
 				data.val = []byte(vs[0])
+				data.vals = toBytes(vs)
 				data.has = true
 			} else if vs := r.PostForm[conf.Name()]; len(vs) > 0 {
 				data.val = []byte(vs[0])
+				data.vals = toBytes(vs)
 				data.has = true
 			}
 		case router.ParamParam:
@@ -82,6 +165,7 @@ func (v *server) handleParameters(c *Context, route router.RouteConfig, params [
 			if queries := r.URL.Query(); queries != nil {
 				if vs, ok := queries[conf.Name()]; ok && len(vs) > 0 {
 					data.val = []byte(vs[0])
+					data.vals = toBytes(vs)
 					data.has = true
 				}
 			}
@@ -89,6 +173,7 @@ func (v *server) handleParameters(c *Context, route router.RouteConfig, params [
 			if headers := textproto.MIMEHeader(r.Header); headers != nil {
 				if vs, ok := headers[conf.Name()]; ok && len(vs) > 0 {
 					data.val = []byte(vs[0])
+					data.vals = toBytes(vs)
 					data.has = true
 				}
 			}
@@ -97,6 +182,7 @@ func (v *server) handleParameters(c *Context, route router.RouteConfig, params [
 				for _, c := range cookies {
 					if c != nil && c.Name == conf.Name() {
 						data.val = []byte(c.Value)
+						data.vals = append(data.vals, data.val)
 						data.has = true
 					}
 				}
@@ -125,7 +211,23 @@ func (v *server) handleParameters(c *Context, route router.RouteConfig, params [
 				}
 			}
 		}
+		// repeated parameters are present when any of their values is, empty
+		// values are dropped so they are not validated, single parameters
+		// keep the value they resolved to
+		if conf.Multiple() && len(data.vals) > 0 {
+			var vals [][]byte
+			for _, b := range data.vals {
+				if len(b) > 0 {
+					vals = append(vals, b)
+				}
+			}
+			data.vals = vals
+			if len(vals) > 0 {
+				data.val = vals[0]
+			}
+		}
 		if len(data.val) == 0 || data.val == nil {
+			data.vals = nil
 			if conf.Require() {
 				warning := fault.
 					For(fault.MissingField).
@@ -136,11 +238,25 @@ func (v *server) handleParameters(c *Context, route router.RouteConfig, params [
 			data.val = conf.Default()
 		}
 		if len(data.val) != 0 && data.val != nil {
-			custom := conf.Custom()
 			depson := conf.DependsOn()
-			switch parsed, ok := router.Val(conf.Format(), data.val); {
-			case ok && (custom == nil || (custom != nil && custom(data.val))):
-				data.parsed = parsed
+			code := ""
+			if conf.Multiple() {
+				if len(data.vals) == 0 {
+					data.vals = [][]byte{data.val}
+				}
+				for _, b := range data.vals {
+					parsed, failed := validate(conf, b)
+					data.list = append(data.list, parsed)
+					if code == "" {
+						code = failed
+					}
+				}
+				data.parsed = data.list[0]
+			} else {
+				data.parsed, code = validate(conf, data.val)
+			}
+			switch code {
+			case "":
 				if len(depson) > 0 {
 					for _, dep := range depson {
 						idx := route.ValueIndex(dep)
@@ -158,7 +274,7 @@ func (v *server) handleParameters(c *Context, route router.RouteConfig, params [
 				}
 			default:
 				warning := fault.
-					For(fault.Invalid).
+					For(code).
 					SetResource(conf.Type().String()).
 					SetField(conf.Name())
 				c.warnings = append(c.warnings, warning)
@@ -216,6 +332,8 @@ func (v *server) handlerRoute(conf router.RouteConfig) func(http.ResponseWriter,
 			res:             w,
 			client:          client,
 			values:          make([]router.Value, 0),
+			route:           conf,
+			validate:        v.opts.ValidateResponses,
 			params:          conf.Params(),
 			warnings:        make([]fault.Cause, 0),
 			cache:           v.cache,
@@ -238,6 +356,7 @@ func (v *server) handlerRoute(conf router.RouteConfig) func(http.ResponseWriter,
 		c.header = h
 		c.cookie = s
 
+		defer c.flush()
 		defer func() {
 			ext.HTTPStatusCode.Set(span, uint16(c.statuscode))
 			if err, ok := recover().(error); ok && err != nil && !c.IsAborted() {
@@ -256,6 +375,25 @@ func (v *server) handlerRoute(conf router.RouteConfig) func(http.ResponseWriter,
 		case "PUT", "POST", "PATCH", "CONNECT":
 			span := c.StartSpan("HTTP ParseMultipartForm")
 			r.Body = http.MaxBytesReader(w, r.Body, conf.MaxMemory())
+			if decoder := decoderFor(r.Header.Get("Content-Type")); decoder != nil && hasBody(conf.Params()) {
+				b, err := ioutil.ReadAll(r.Body)
+				r.Body = ioutil.NopCloser(bytes.NewReader(b))
+				if err != nil {
+					warning := fault.
+						For(fault.Invalid).
+						SetResource(router.ParamBody.String())
+					c.warnings = append(c.warnings, warning)
+				} else if len(b) > 0 {
+					payload, err := decoder(b)
+					if err != nil {
+						warning := fault.
+							For(fault.Invalid).
+							SetResource(router.ParamBody.String())
+						c.warnings = append(c.warnings, warning)
+					}
+					c.payload = payload
+				}
+			}
 			r.ParseMultipartForm(conf.MaxMemory())
 			if r.MultipartForm != nil {
 				defer r.MultipartForm.RemoveAll()
@@ -349,6 +487,53 @@ func (v *server) buildRoutes(mux *chi.Mux, routes []router.Route) {
 				Config(),
 		))
 	}
+	if v.gws != nil {
+		handler := func(c router.Context) {
+			v.gws.HandleSSE(c)
+		}
+		mux.Get("/_s/graphql", v.handlerRoute(
+			v.router.Get("/_s/graphql").
+				Name("GraphQLSubscriptionEvents").
+				Doc(`GraphQL subscription over server-sent events`).
+				Summary(`GraphQL subscription over server-sent events`).
+				ClearParams().
+				ClearMiddleware().
+				Handle(handler).
+				Config(),
+		))
+		mux.Post("/_s/graphql", v.handlerRoute(
+			v.router.Post("/_s/graphql").
+				Name("PostGraphQLSubscriptionEvents").
+				Doc(`GraphQL subscription over server-sent events`).
+				Summary(`GraphQL subscription over server-sent events`).
+				ClearParams().
+				ClearMiddleware().
+				Handle(handler).
+				Config(),
+		))
+	}
+}
+
+func (v *server) buildSwagger(mux *chi.Mux) {
+	var (
+		doc  = swagger.Generate(v.router, v.opts.Swagger...)
+		path = strings.TrimSuffix(v.opts.SwaggerPath, "/")
+		json = doc.JSON()
+		yaml = doc.YAML()
+		ui   = swagger.UI(doc.Info.Title, path+"/openapi.json", v.opts.Swagger...)
+	)
+	mux.Get(path+"/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(json)
+	})
+	mux.Get(path+"/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-yaml; charset=utf-8")
+		w.Write(yaml)
+	})
+	mux.Get(path+"/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(ui)
+	})
 }
 
 func (v *server) Start() error {
@@ -397,6 +582,11 @@ func (v *server) Start() error {
 
 	v.buildRoutes(mux, v.router.Routes())
 
+	// serve OpenAPI document and swagger ui
+	if v.opts.EnableSwagger {
+		v.buildSwagger(mux)
+	}
+
 	// create http server
 	srv := &http.Server{
 		Addr:    v.addr,
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vaniila/hyper/router"
+)
+
+// testParam is a route parameter without the builder of the hyper package
+type testParam struct {
+	router.Param
+	conf *testParamConfig
+}
+
+type testParamConfig struct {
+	router.ParamConfig
+	name     string
+	typ      router.ParamType
+	format   int
+	def      []byte
+	require  bool
+	multiple bool
+}
+
+func param(typ router.ParamType, name string, format int) *testParam {
+	return &testParam{conf: &testParamConfig{name: name, typ: typ, format: format}}
+}
+
+func (v *testParam) Config() router.ParamConfig { return v.conf }
+
+func (v *testParam) multiple() *testParam {
+	v.conf.multiple = true
+	return v
+}
+
+func (v *testParam) required() *testParam {
+	v.conf.require = true
+	return v
+}
+
+func (v *testParam) fallback(s string) *testParam {
+	v.conf.def = []byte(s)
+	return v
+}
+
+func (v *testParamConfig) Name() string              { return v.name }
+func (v *testParamConfig) Type() router.ParamType    { return v.typ }
+func (v *testParamConfig) Custom() router.CustomFunc { return nil }
+func (v *testParamConfig) Format() int               { return v.format }
+func (v *testParamConfig) Default() []byte           { return v.def }
+func (v *testParamConfig) Require() bool             { return v.require }
+func (v *testParamConfig) Multiple() bool            { return v.multiple }
+func (v *testParamConfig) Min() *float64             { return nil }
+func (v *testParamConfig) Max() *float64             { return nil }
+func (v *testParamConfig) MinLength() *int           { return nil }
+func (v *testParamConfig) MaxLength() *int           { return nil }
+func (v *testParamConfig) Pattern() *regexp.Regexp   { return nil }
+func (v *testParamConfig) Enum() []string            { return nil }
+func (v *testParamConfig) DependsOn() []router.Param { return nil }
+func (v *testParamConfig) OneOf() []router.Param     { return nil }
+
+// resolveParams runs the parameters against a request with the query and cookies
+func resolveParams(query, cookie string, params ...router.Param) *Context {
+	r := httptest.NewRequest("GET", "/?"+query, nil)
+	if cookie != "" {
+		r.Header.Set("Cookie", cookie)
+	}
+	c := &Context{req: r, params: params}
+	new(server).handleParameters(c, nil, params)
+	return c
+}
+
+func TestHandleParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		cookie   string
+		param    *testParam
+		val      string
+		vals     string
+		warnings int
+	}{
+		{"single", "q=a&q=b", "", param(router.ParamQuery, "q", router.Text), "a", "a b", 0},
+		{"single empty first", "q=&q=x", "", param(router.ParamQuery, "q", router.Text), "", "", 0},
+		{"single missing default", "", "", param(router.ParamQuery, "q", router.Text).fallback("d"), "d", "d", 0},
+		{"single required", "q=", "", param(router.ParamQuery, "q", router.Text).required(), "", "", 1},
+		{"single cookie keeps the last", "", "c=1; c=2", param(router.ParamCookie, "c", router.Text), "2", "1 2", 0},
+		{"multiple", "q=a&q=b", "", param(router.ParamQuery, "q", router.Text).multiple(), "a", "a b", 0},
+		{"multiple drops empty values", "q=&q=x&q=", "", param(router.ParamQuery, "q", router.Text).multiple(), "x", "x", 0},
+		{"multiple all empty", "q=&q=", "", param(router.ParamQuery, "q", router.Text).multiple().fallback("d"), "d", "d", 0},
+		{"multiple required", "q=&q=", "", param(router.ParamQuery, "q", router.Text).multiple().required(), "", "", 1},
+		{"multiple cookies", "", "c=1; c=2", param(router.ParamCookie, "c", router.Text).multiple(), "1", "1 2", 0},
+		{"multiple invalid", "q=1&q=x", "", param(router.ParamQuery, "q", router.Int).multiple(), "1", "1 x", 1},
+	}
+	for _, tt := range tests {
+		c := resolveParams(tt.query, tt.cookie, tt.param)
+		val := c.values[0].(*Value)
+		var vals []string
+		for _, b := range val.Vals() {
+			vals = append(vals, string(b))
+		}
+		if string(val.Val()) != tt.val || strings.Join(vals, " ") != tt.vals {
+			t.Errorf("%s: resolved %q and %q, want %q and %q", tt.name, val.Val(), vals, tt.val, tt.vals)
+		}
+		if len(c.warnings) != tt.warnings {
+			t.Errorf("%s: %d warnings, want %d", tt.name, len(c.warnings), tt.warnings)
+		}
+	}
+}
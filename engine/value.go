@@ -14,8 +14,10 @@ type Value struct {
 	fmt    int
 	key    string
 	val    []byte
+	vals   [][]byte
 	has    bool
 	parsed interface{}
+	list   []interface{}
 }
 
 // Type of value
@@ -33,6 +35,19 @@ func (v *Value) Val() []byte {
 	return v.val
 }
 
+// Vals are all the raw values received for the key
+func (v *Value) Vals() [][]byte {
+	if len(v.vals) == 0 && len(v.val) > 0 {
+		return [][]byte{v.val}
+	}
+	return v.vals
+}
+
+// Parsed is the data converted by the parameter format
+func (v *Value) Parsed() interface{} {
+	return v.parsed
+}
+
 // ThrowIllegal throws illegal type error
 func (v *Value) ThrowIllegal(cause fault.Cause) {
 	err := fault.
@@ -165,6 +180,126 @@ func (v *Value) MustTime() time.Time {
 	return v.parsed.(time.Time)
 }
 
+// parsedList returns every parsed value if the format is whitelisted
+func (v *Value) parsedList(resource string, formats ...int) []interface{} {
+	allowed := false
+	for _, f := range formats {
+		allowed = allowed || v.fmt == f
+	}
+	if !allowed {
+		v.ThrowIllegal(
+			fault.
+				For(fault.Illegal).
+				SetResource(resource).
+				SetField(v.key),
+		)
+	}
+	if v.list == nil && v.parsed != nil {
+		return []interface{}{v.parsed}
+	}
+	return v.list
+}
+
+// MustStrings returns all values in string format
+func (v *Value) MustStrings() []string {
+	vals := v.Vals()
+	a := make([]string, len(vals))
+	for i, b := range vals {
+		a[i] = string(b)
+	}
+	return a
+}
+
+// MustInts returns all values in int format
+func (v *Value) MustInts() []int {
+	list := v.parsedList("MustInts", router.Int)
+	a := make([]int, len(list))
+	for i, o := range list {
+		a[i] = o.(int)
+	}
+	return a
+}
+
+// MustI32s returns all values in int32 format
+func (v *Value) MustI32s() []int32 {
+	list := v.parsedList("MustI32s", router.I32)
+	a := make([]int32, len(list))
+	for i, o := range list {
+		a[i] = o.(int32)
+	}
+	return a
+}
+
+// MustI64s returns all values in int64 format
+func (v *Value) MustI64s() []int64 {
+	list := v.parsedList("MustI64s", router.I64)
+	a := make([]int64, len(list))
+	for i, o := range list {
+		a[i] = o.(int64)
+	}
+	return a
+}
+
+// MustU32s returns all values in uint32 format
+func (v *Value) MustU32s() []uint32 {
+	list := v.parsedList("MustU32s", router.U32)
+	a := make([]uint32, len(list))
+	for i, o := range list {
+		a[i] = o.(uint32)
+	}
+	return a
+}
+
+// MustU64s returns all values in uint64 format
+func (v *Value) MustU64s() []uint64 {
+	list := v.parsedList("MustU64s", router.U64)
+	a := make([]uint64, len(list))
+	for i, o := range list {
+		a[i] = o.(uint64)
+	}
+	return a
+}
+
+// MustF32s returns all values in float32 format
+func (v *Value) MustF32s() []float32 {
+	list := v.parsedList("MustF32s", router.F32)
+	a := make([]float32, len(list))
+	for i, o := range list {
+		a[i] = o.(float32)
+	}
+	return a
+}
+
+// MustF64s returns all values in float64 format
+func (v *Value) MustF64s() []float64 {
+	list := v.parsedList("MustF64s", router.F64, router.Lat, router.Lon)
+	a := make([]float64, len(list))
+	for i, o := range list {
+		a[i] = o.(float64)
+	}
+	return a
+}
+
+// MustBools returns all values in boolean format
+func (v *Value) MustBools() []bool {
+	list := v.parsedList("MustBools", router.Bool)
+	a := make([]bool, len(list))
+	for i, o := range list {
+		a[i] = o.(bool)
+	}
+	return a
+}
+
+// MustTimes returns all values in time.Time format
+func (v *Value) MustTimes() []time.Time {
+	list := v.parsedList("MustTimes", router.DateTimeRFC822, router.DateTimeRFC3339, router.DateTimeUnix)
+	a := make([]time.Time, len(list))
+	for i, o := range list {
+		a[i] = o.(time.Time)
+	}
+	return a
+}
+
 // Has represents if input exists
 func (v *Value) Has() bool {
 	return v.has
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/vaniila/hyper"
+	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/swagger"
+)
+
+func main() {
+
+	h := hyper.New(
+		hyper.Addr(":4000"),
+		hyper.HTTP2(),
+		hyper.Swagger(
+			swagger.Title("Greeting API"),
+			swagger.Version("1.0.0"),
+		),
+	)
+
+	ro := h.Router()
+
+	ro.
+		Get("/").
+		Name("greet").
+		Summary(`greets the caller`).
+		Params(
+			hyper.Query("message").
+				Format(hyper.Text).
+				Doc(`custom greeting message`).
+				Summary(`custom greeting message`).
+				Default([]byte("hello world")).
+				Require(false),
+		).
+		Handle(func(c router.Context) {
+			c.Write(c.MustQuery("message").Val())
+		})
+
+	// document is served at /swagger/openapi.json and the ui at /swagger/
+	h.Run()
+}
@@ -9,6 +9,12 @@ var (
 	Invalid           = string("invalid")
 	AlreadyExists     = string("already_exists")
 	Conflict          = string("conflict")
+	TooSmall          = string("too_small")
+	TooLarge          = string("too_large")
+	TooShort          = string("too_short")
+	TooLong           = string("too_long")
+	Mismatch          = string("mismatch")
+	NotAllowed        = string("not_allowed")
 )
 
 // Reason struct
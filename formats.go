@@ -1,6 +1,9 @@
 package hyper
 
-import "github.com/vaniila/hyper/router"
+import (
+	"github.com/vaniila/hyper/engine"
+	"github.com/vaniila/hyper/router"
+)
 
 const (
 	Any             = router.Any
@@ -24,3 +27,13 @@ const (
 	DateTimeRFC3339 = router.DateTimeRFC3339
 	DateTimeUnix    = router.DateTimeUnix
 )
+
+// RegisterFormat adds a custom parameter format and returns its id
+func RegisterFormat(name string, parser router.FormatParser) int {
+	return router.RegisterFormat(name, parser)
+}
+
+// RegisterEncoder adds a response encoder used by Render for a media type
+func RegisterEncoder(mediaType string, encoder router.Encoder) {
+	engine.RegisterEncoder(mediaType, encoder)
+}
@@ -10,6 +10,7 @@ type argument struct {
 	typ               graphql.Input
 	obj               gql.Object
 	def               interface{}
+	format            int
 	require           bool
 	initialized       bool
 	conf              gql.ArgumentConfig
@@ -40,6 +41,11 @@ func (v *argument) Default(o interface{}) gql.Argument {
 	return v
 }
 
+func (v *argument) Format(f int) gql.Argument {
+	v.format = f
+	return v
+}
+
 func (v *argument) Require(b bool) gql.Argument {
 	v.require = b
 	return v
@@ -35,6 +35,10 @@ func (v *argumentconfig) Default() interface{} {
 	return v.argument.def
 }
 
+func (v *argumentconfig) Format() int {
+	return v.argument.format
+}
+
 func (v *argumentconfig) Require() bool {
 	return v.argument.require
 }
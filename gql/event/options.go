@@ -1,5 +1,7 @@
 package event
 
+import "github.com/vaniila/hyper/router"
+
 // Option func
 type Option func(*Options)
 
@@ -40,8 +42,53 @@ func Payload(b []byte) Option {
 // Filters to set filters option
 func Filters(s map[string]interface{}) Option {
 	return func(o *Options) {
-		o.Filters = s
+		for k, v := range s {
+			filter(o, k, v)
+		}
+	}
+}
+
+// In to match subscriptions with argument equal to any of the values
+func In(key string, values ...interface{}) Option {
+	return func(o *Options) {
+		filter(o, key, &router.GQLFilter{Operator: router.FilterIn, Values: values})
+	}
+}
+
+// NotIn to match subscriptions with argument equal to none of the values
+func NotIn(key string, values ...interface{}) Option {
+	return func(o *Options) {
+		filter(o, key, &router.GQLFilter{Operator: router.FilterNotIn, Values: values})
+	}
+}
+
+// Range to match subscriptions with argument between min and max inclusively,
+// a nil bound is left open
+func Range(key string, min, max interface{}) Option {
+	return func(o *Options) {
+		filter(o, key, &router.GQLFilter{Operator: router.FilterRange, Min: min, Max: max})
+	}
+}
+
+// Prefix to match subscriptions with string or bytes argument starting with prefix
+func Prefix(key string, prefix interface{}) Option {
+	return func(o *Options) {
+		filter(o, key, &router.GQLFilter{Operator: router.FilterPrefix, Values: []interface{}{prefix}})
+	}
+}
+
+// Absent to match subscriptions without the argument
+func Absent(key string) Option {
+	return func(o *Options) {
+		filter(o, key, &router.GQLFilter{Operator: router.FilterAbsent})
+	}
+}
+
+func filter(o *Options, key string, v interface{}) {
+	if o.Filters == nil {
+		o.Filters = make(map[string]interface{})
 	}
+	o.Filters[key] = v
 }
 
 // EqIDs to set equal ids option
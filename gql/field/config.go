@@ -1,10 +1,12 @@
 package field
 
 import (
+	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/graphql-go/graphql"
+	"github.com/vaniila/hyper/fault"
 	"github.com/vaniila/hyper/gql"
 	"github.com/vaniila/hyper/gql/server"
 	"github.com/vaniila/hyper/router"
@@ -39,6 +41,18 @@ func (v *fieldconfig) Args() []gql.Argument {
 	return v.field.args
 }
 
+func (v *fieldconfig) Subscribe() gql.SubscribeHandler {
+	return v.field.subscribe
+}
+
+func (v *fieldconfig) OnSubscribe() gql.OnSubscribeHandler {
+	return v.field.onsubscribe
+}
+
+func (v *fieldconfig) OnUnsubscribe() gql.OnUnsubscribeHandler {
+	return v.field.onunsubscribe
+}
+
 func (v *fieldconfig) Field() *graphql.Field {
 	if v.compiled == nil {
 		v.compiled = &compiled{}
@@ -63,7 +77,33 @@ func (v *fieldconfig) Field() *graphql.Field {
 				params:  params,
 				values:  make([]gql.Value, len(v.field.args)),
 			}
-			v.Resolve(params.Args, r.values, v.field.args)
+			if err := v.Resolve(params.Args, r.values, v.field.args); err != nil {
+				r.Context().GraphQLError(err)
+				return nil, err
+			}
+			// subscription filtering and lifecycle run the subscription
+			// handlers instead
+			if m, ok := params.Source.(map[string]interface{}); ok {
+				if d, ok := m["$subscription_filter$"]; ok {
+					if v.field.subscribe == nil {
+						return true, nil
+					}
+					b, _ := d.([]byte)
+					return v.field.subscribe(b, r), nil
+				}
+				if _, ok := m["$subscription_start$"]; ok {
+					if v.field.onsubscribe == nil {
+						return nil, nil
+					}
+					return v.field.onsubscribe(r)
+				}
+				if _, ok := m["$subscription_stop$"]; ok {
+					if v.field.onunsubscribe != nil {
+						v.field.onunsubscribe(r)
+					}
+					return nil, nil
+				}
+			}
 			o, err := v.field.resolve(r)
 			if err != nil {
 				r.Context().GraphQLError(err)
@@ -86,7 +126,9 @@ func (v *fieldconfig) Field() *graphql.Field {
 	return v.compiled.field
 }
 
-func (v *fieldconfig) Resolve(params map[string]interface{}, values []gql.Value, args []gql.Argument) {
+// Resolve parses the arguments into values, arguments failing their format
+// are rejected
+func (v *fieldconfig) Resolve(params map[string]interface{}, values []gql.Value, args []gql.Argument) error {
 	for i, arg := range args {
 		conf := arg.Config().ArgumentConfig()
 		data := &value{
@@ -108,6 +150,10 @@ func (v *fieldconfig) Resolve(params map[string]interface{}, values []gql.Value,
 		default:
 			data.fmt = router.Any
 		}
+		format := arg.Config().Format()
+		if format != router.Any {
+			data.fmt = format
+		}
 		if k, ok := params[data.key]; ok {
 			switch o := k.(type) {
 			case []byte:
@@ -117,10 +163,15 @@ func (v *fieldconfig) Resolve(params map[string]interface{}, values []gql.Value,
 			case string:
 				data.val = []byte(o)
 				data.has = true
-				if data.fmt == router.DateTimeRFC3339 {
-					t, _ := time.Parse(time.RFC3339, o)
-					data.parsed = t
-				} else {
+				switch {
+				case data.fmt == router.DateTimeRFC3339:
+					// invalid times are rejected below
+					if t, err := time.Parse(time.RFC3339, o); err == nil {
+						data.parsed = t
+					}
+				case format != router.Any:
+					// declared formats are parsed from the raw value below
+				default:
 					data.parsed = o
 				}
 			case int:
@@ -144,7 +195,9 @@ func (v *fieldconfig) Resolve(params map[string]interface{}, values []gql.Value,
 				data.has = true
 				if args := arg.Config().Object().Config().Args(); len(args) > 0 {
 					arr := make([]gql.Value, len(args))
-					v.Resolve(o, arr, args)
+					if err := v.Resolve(o, arr, args); err != nil {
+						return err
+					}
 					data.parsed = arr
 				}
 			case []interface{}:
@@ -164,10 +217,21 @@ func (v *fieldconfig) Resolve(params map[string]interface{}, values []gql.Value,
 			}
 		}
 		if data.val != nil && data.parsed == nil {
-			if parsed, ok := router.Val(data.fmt, data.val); ok {
-				data.parsed = parsed
+			parsed, ok := router.Val(data.fmt, data.val)
+			if !ok {
+				return fault.
+					New("Illegal Field Entity").
+					SetStatus(http.StatusUnprocessableEntity).
+					AddCause(
+						fault.
+							For(fault.Invalid).
+							SetResource("argument").
+							SetField(data.key),
+					)
 			}
+			data.parsed = parsed
 		}
 		values[i] = data
 	}
+	return nil
 }
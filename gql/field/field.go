@@ -12,6 +12,9 @@ type field struct {
 	args                          []gql.Argument
 	argsMap                       map[string]struct{}
 	resolve                       gql.ResolveHandler
+	subscribe                     gql.SubscribeHandler
+	onsubscribe                   gql.OnSubscribeHandler
+	onunsubscribe                 gql.OnUnsubscribeHandler
 	initialized                   bool
 	conf                          gql.FieldConfig
 }
@@ -61,6 +64,21 @@ func (v *field) Resolve(h gql.ResolveHandler) gql.Field {
 	return v
 }
 
+func (v *field) Subscribe(h gql.SubscribeHandler) gql.Field {
+	v.subscribe = h
+	return v
+}
+
+func (v *field) OnSubscribe(h gql.OnSubscribeHandler) gql.Field {
+	v.onsubscribe = h
+	return v
+}
+
+func (v *field) OnUnsubscribe(h gql.OnUnsubscribeHandler) gql.Field {
+	v.onunsubscribe = h
+	return v
+}
+
 func (v *field) Init(fn gql.FieldInitializer) gql.Field {
 	if !v.initialized {
 		v.initialized = true
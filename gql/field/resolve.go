@@ -27,6 +27,15 @@ func (v *resolve) Source() interface{} {
 		if d, ok := m["$subscription_payload$"]; ok {
 			return d
 		}
+		if d, ok := m["$subscription_filter$"]; ok {
+			return d
+		}
+		if _, ok := m["$subscription_start$"]; ok {
+			return nil
+		}
+		if _, ok := m["$subscription_stop$"]; ok {
+			return nil
+		}
 	}
 	return v.params.Source
 }
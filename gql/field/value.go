@@ -182,6 +182,11 @@ func (v *value) Any() interface{} {
 	return v.parsed
 }
 
+// Parsed returns the value parsed by its format, custom formats included
+func (v *value) Parsed() interface{} {
+	return v.parsed
+}
+
 // Has represents if input exists
 func (v *value) Has() bool {
 	return v.has
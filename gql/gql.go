@@ -23,6 +23,16 @@ type ArgumentInitializer func(Argument)
 // ResolveHandler for field resolve
 type ResolveHandler func(Resolver) (interface{}, error)
 
+// SubscribeHandler decides whether a subscription receives an event payload
+type SubscribeHandler func([]byte, Resolver) bool
+
+// OnSubscribeHandler runs when a client subscribes to a field, an error
+// rejects the subscription and a payload is resolved as its initial event
+type OnSubscribeHandler func(Resolver) ([]byte, error)
+
+// OnUnsubscribeHandler runs when a subscription to a field ends
+type OnUnsubscribeHandler func(Resolver)
+
 // ScalarSerializeHandler for scalar serialize resolver
 type ScalarSerializeHandler func(interface{}) (interface{}, error)
 
@@ -140,6 +150,9 @@ type Field interface {
 	Type(interface{}) Field
 	Args(...Argument) Field
 	Resolve(ResolveHandler) Field
+	Subscribe(SubscribeHandler) Field
+	OnSubscribe(OnSubscribeHandler) Field
+	OnUnsubscribe(OnUnsubscribeHandler) Field
 	Init(FieldInitializer) Field
 	Config() FieldConfig
 }
@@ -151,6 +164,9 @@ type FieldConfig interface {
 	DeprecationReason() string
 	Type() graphql.Output
 	Args() []Argument
+	Subscribe() SubscribeHandler
+	OnSubscribe() OnSubscribeHandler
+	OnUnsubscribe() OnUnsubscribeHandler
 	Field() *graphql.Field
 }
 
@@ -159,6 +175,7 @@ type Argument interface {
 	Description(string) Argument
 	Type(interface{}) Argument
 	Default(interface{}) Argument
+	Format(int) Argument
 	Require(bool) Argument
 	Init(ArgumentInitializer) Argument
 	Config() ArgumentConfig
@@ -171,6 +188,7 @@ type ArgumentConfig interface {
 	Type() graphql.Input
 	Object() Object
 	Default() interface{}
+	Format() int
 	Require() bool
 	ArgumentConfig() *graphql.ArgumentConfig
 	InputObjectFieldConfig() *graphql.InputObjectFieldConfig
@@ -202,6 +220,7 @@ type Value interface {
 	MustTime() time.Time
 	MustArray() []interface{}
 	Any() interface{}
+	Parsed() interface{}
 	String() string
 }
 
@@ -232,6 +251,7 @@ type Context interface {
 	Param(s string) (router.Value, error)
 	Query(s string) (router.Value, error)
 	Body(s string) (router.Value, error)
+	Bind(interface{}) error
 	File(s string) []byte
 	StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span
 	Tracer() opentracing.Tracer
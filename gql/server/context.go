@@ -42,6 +42,7 @@ func (v *ctx) MustBody(s string) router.Value                    { return v.priv
 func (v *ctx) Param(s string) (router.Value, error)              { return v.private.Param(s) }
 func (v *ctx) Query(s string) (router.Value, error)              { return v.private.Query(s) }
 func (v *ctx) Body(s string) (router.Value, error)               { return v.private.Body(s) }
+func (v *ctx) Bind(o interface{}) error                          { return v.private.Bind(o) }
 func (v *ctx) File(s string) []byte                              { return v.private.File(s) }
 func (v *ctx) Tracer() opentracing.Tracer                        { return v.private.Tracer() }
 func (v *ctx) Abort()                                            { v.private.Abort() }
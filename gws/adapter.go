@@ -10,54 +10,47 @@ type adaptor struct {
 	s Service
 }
 
+var operators = map[router.GQLFilterOperator]Operator{
+	router.FilterIn:     Operator_IN,
+	router.FilterNotIn:  Operator_NOT_IN,
+	router.FilterRange:  Operator_RANGE,
+	router.FilterPrefix: Operator_PREFIX,
+	router.FilterAbsent: Operator_ABSENT,
+}
+
 func (v *adaptor) Emit(e router.GQLEvent) error {
-	var idx int
 	d := &Distribution{
 		Field:   e.Field(),
 		Payload: e.Payload(),
-		Filters: make([]*Filter, len(e.Filters())),
+		Filters: make([]*Filter, 0, len(e.Filters())),
 		Strict:  e.Strict(),
 	}
 	for k, v := range e.Filters() {
 		o := &Filter{
 			Key: k,
 		}
-		switch i := v.(type) {
-		case string:
-			o.ValOneof = &Filter_StringValue{i}
-		case int:
-			o.ValOneof = &Filter_IntValue{int64(i)}
-		case int8:
-			o.ValOneof = &Filter_IntValue{int64(i)}
-		case int16:
-			o.ValOneof = &Filter_IntValue{int64(i)}
-		case int32:
-			o.ValOneof = &Filter_IntValue{int64(i)}
-		case int64:
-			o.ValOneof = &Filter_IntValue{i}
-		case uint:
-			o.ValOneof = &Filter_IntValue{int64(i)}
-		case uint8:
-			o.ValOneof = &Filter_IntValue{int64(i)}
-		case uint16:
-			o.ValOneof = &Filter_IntValue{int64(i)}
-		case uint32:
-			o.ValOneof = &Filter_IntValue{int64(i)}
-		case uint64:
-			o.ValOneof = &Filter_IntValue{int64(i)}
-		case float32:
-			o.ValOneof = &Filter_FloatValue{float64(i)}
-		case float64:
-			o.ValOneof = &Filter_FloatValue{i}
-		case bool:
-			o.ValOneof = &Filter_BoolValue{i}
-		case []byte:
-			o.ValOneof = &Filter_BytesValue{i}
-		case time.Time:
-			o.ValOneof = &Filter_TimeValue{i.UnixNano()}
+		if f, ok := v.(*router.GQLFilter); ok {
+			o.Operator = operators[f.Operator]
+			switch f.Operator {
+			case router.FilterPrefix:
+				if len(f.Values) > 0 {
+					if val := newValue(f.Values[0]); val != nil {
+						o.ValOneof = filterOneof(val)
+					}
+				}
+			default:
+				for _, i := range f.Values {
+					if val := newValue(i); val != nil {
+						o.Values = append(o.Values, val)
+					}
+				}
+				o.Min = newValue(f.Min)
+				o.Max = newValue(f.Max)
+			}
+		} else if val := newValue(v); val != nil {
+			o.ValOneof = filterOneof(val)
 		}
-		d.Filters[idx] = o
-		idx++
+		d.Filters = append(d.Filters, o)
 	}
 	if len(e.EqIDs()) > 0 || len(e.NeIDs()) > 0 || len(e.EqKeys()) > 0 || len(e.NeKeys()) > 0 {
 		d.Condition = &Condition{
@@ -69,3 +62,62 @@ func (v *adaptor) Emit(e router.GQLEvent) error {
 	}
 	return v.s.Publish(d)
 }
+
+// newValue converts go values into distribution values, unsupported types
+// return nil
+func newValue(v interface{}) *Value {
+	switch i := v.(type) {
+	case string:
+		return &Value{ValOneof: &Value_StringValue{i}}
+	case int:
+		return &Value{ValOneof: &Value_IntValue{int64(i)}}
+	case int8:
+		return &Value{ValOneof: &Value_IntValue{int64(i)}}
+	case int16:
+		return &Value{ValOneof: &Value_IntValue{int64(i)}}
+	case int32:
+		return &Value{ValOneof: &Value_IntValue{int64(i)}}
+	case int64:
+		return &Value{ValOneof: &Value_IntValue{i}}
+	case uint:
+		return &Value{ValOneof: &Value_IntValue{int64(i)}}
+	case uint8:
+		return &Value{ValOneof: &Value_IntValue{int64(i)}}
+	case uint16:
+		return &Value{ValOneof: &Value_IntValue{int64(i)}}
+	case uint32:
+		return &Value{ValOneof: &Value_IntValue{int64(i)}}
+	case uint64:
+		return &Value{ValOneof: &Value_IntValue{int64(i)}}
+	case float32:
+		return &Value{ValOneof: &Value_FloatValue{float64(i)}}
+	case float64:
+		return &Value{ValOneof: &Value_FloatValue{i}}
+	case bool:
+		return &Value{ValOneof: &Value_BoolValue{i}}
+	case []byte:
+		return &Value{ValOneof: &Value_BytesValue{i}}
+	case time.Time:
+		return &Value{ValOneof: &Value_TimeValue{i.UnixNano()}}
+	}
+	return nil
+}
+
+// filterOneof converts a value into the equality operand of a filter
+func filterOneof(v *Value) isFilter_ValOneof {
+	switch o := v.GetValOneof().(type) {
+	case *Value_StringValue:
+		return &Filter_StringValue{o.StringValue}
+	case *Value_IntValue:
+		return &Filter_IntValue{o.IntValue}
+	case *Value_FloatValue:
+		return &Filter_FloatValue{o.FloatValue}
+	case *Value_BoolValue:
+		return &Filter_BoolValue{o.BoolValue}
+	case *Value_BytesValue:
+		return &Filter_BytesValue{o.BytesValue}
+	case *Value_TimeValue:
+		return &Filter_TimeValue{o.TimeValue}
+	}
+	return nil
+}
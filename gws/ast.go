@@ -1,62 +1,249 @@
 package gws
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
-	"github.com/graphql-go/graphql/language/kinds"
 )
 
-func operationDefinitionsWithOperation(doc *ast.Document, op string) []*ast.OperationDefinition {
-	defs := []*ast.OperationDefinition{}
+// getSubscriptionInfo returns the root field of the subscription operation
+// and its arguments, variables and literals are resolved to go values
+func getSubscriptionInfo(schema *graphql.Schema, doc *ast.Document, opname string, vars map[string]interface{}) ([]string, map[string]interface{}, error) {
+
+	def, err := subscriptionOperation(doc, opname)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fields []*ast.Field
+	collectFields(def.SelectionSet, fragmentDefinitions(doc), make(map[string]struct{}), &fields)
+
+	keys := make(map[string]struct{})
+	for _, field := range fields {
+		key := field.Name.Value
+		if field.Alias != nil {
+			key = field.Alias.Value
+		}
+		keys[key] = struct{}{}
+	}
+	if len(keys) != 1 {
+		if def.Name != nil {
+			return nil, nil, fmt.Errorf("Subscription \"%s\" must select only one top level field.", def.Name.Value)
+		}
+		return nil, nil, errors.New("Anonymous Subscription must select only one top level field.")
+	}
+
+	field := fields[0]
+	name := field.Name.Value
+	if strings.HasPrefix(name, "__") {
+		return nil, nil, errors.New("Subscription must not select an introspection top level field.")
+	}
+
+	// variables not provided fall back to their default values
+	values := make(map[string]interface{})
+	for _, vd := range def.VariableDefinitions {
+		if vd.DefaultValue != nil {
+			values[vd.Variable.Name.Value] = valueFromAST(vd.DefaultValue, nil)
+		}
+	}
+	for k, v := range vars {
+		values[k] = v
+	}
+
+	types := make(map[string]graphql.Input)
+	if typ := schema.SubscriptionType(); typ != nil {
+		if fd, ok := typ.Fields()[name]; ok {
+			for _, arg := range fd.Args {
+				types[arg.PrivateName] = arg.Type
+			}
+		}
+	}
+
+	args := make(map[string]interface{})
+	for _, arg := range field.Arguments {
+		key := arg.Name.Value
+		args[key] = coerceValue(types[key], valueFromAST(arg.Value, values))
+	}
+
+	return []string{name}, args, nil
+}
+
+// subscriptionOperation picks the operation to execute, by name when the
+// document holds more than one
+func subscriptionOperation(doc *ast.Document, opname string) (*ast.OperationDefinition, error) {
+	var ops []*ast.OperationDefinition
 	for _, node := range doc.Definitions {
-		if node.GetKind() == kinds.OperationDefinition {
-			if def, ok := node.(*ast.OperationDefinition); ok {
-				if def.Operation == op {
-					defs = append(defs, def)
-				}
+		if def, ok := node.(*ast.OperationDefinition); ok {
+			if opname == "" || (def.Name != nil && def.Name.Value == opname) {
+				ops = append(ops, def)
 			}
 		}
 	}
-	return defs
+	switch {
+	case len(ops) == 0 && opname != "":
+		return nil, fmt.Errorf("Unknown operation named \"%s\".", opname)
+	case len(ops) == 0:
+		return nil, errors.New("Must provide an operation.")
+	case len(ops) > 1:
+		return nil, errors.New("Must provide operation name if query contains multiple operations.")
+	}
+	if ops[0].Operation != ast.OperationTypeSubscription {
+		return nil, fmt.Errorf("Operation of type %s is not supported, expected subscription.", ops[0].Operation)
+	}
+	return ops[0], nil
 }
 
-func selectionSetsForOperationDefinitions(defs []*ast.OperationDefinition) []*ast.SelectionSet {
-	sets := []*ast.SelectionSet{}
-	for _, def := range defs {
-		if set := def.GetSelectionSet(); set != nil {
-			sets = append(sets, set)
+func fragmentDefinitions(doc *ast.Document) map[string]*ast.FragmentDefinition {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, node := range doc.Definitions {
+		if frag, ok := node.(*ast.FragmentDefinition); ok {
+			fragments[frag.Name.Value] = frag
 		}
 	}
-	return sets
+	return fragments
 }
 
-func getSubscriptionInfo(doc *ast.Document, vars map[string]interface{}) ([]string, map[string]interface{}) {
+// queryCost measures the depth and the number of selected fields of the
+// operation, fragments count wherever they are spread
+func queryCost(doc *ast.Document, opname string) (int, int) {
+	def, err := subscriptionOperation(doc, opname)
+	if err != nil {
+		return 0, 0
+	}
+	return selectionCost(def.SelectionSet, fragmentDefinitions(doc), make(map[string]struct{}))
+}
 
-	var names = make([]string, 0)
-	var args = make(map[string]interface{})
+func selectionCost(set *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, visiting map[string]struct{}) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, c int
+		switch o := selection.(type) {
+		case *ast.Field:
+			d, c = selectionCost(o.SelectionSet, fragments, visiting)
+			d, c = d+1, c+1
+		case *ast.InlineFragment:
+			d, c = selectionCost(o.SelectionSet, fragments, visiting)
+		case *ast.FragmentSpread:
+			name := o.Name.Value
+			frag, ok := fragments[name]
+			if _, cycle := visiting[name]; !ok || cycle {
+				continue
+			}
+			visiting[name] = struct{}{}
+			d, c = selectionCost(frag.SelectionSet, fragments, visiting)
+			delete(visiting, name)
+		}
+		if d > depth {
+			depth = d
+		}
+		complexity += c
+	}
+	return depth, complexity
+}
 
-	defs := operationDefinitionsWithOperation(doc, "subscription")
-	sets := selectionSetsForOperationDefinitions(defs)
+// collectFields flattens fragment spreads and inline fragments of a
+// selection set into its fields
+func collectFields(set *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, visited map[string]struct{}, fields *[]*ast.Field) {
+	if set == nil {
+		return
+	}
+	for _, selection := range set.Selections {
+		switch o := selection.(type) {
+		case *ast.Field:
+			*fields = append(*fields, o)
+		case *ast.InlineFragment:
+			collectFields(o.SelectionSet, fragments, visited, fields)
+		case *ast.FragmentSpread:
+			name := o.Name.Value
+			if _, ok := visited[name]; ok {
+				continue
+			}
+			visited[name] = struct{}{}
+			if frag, ok := fragments[name]; ok {
+				collectFields(frag.SelectionSet, fragments, visited, fields)
+			}
+		}
+	}
+}
+
+// valueFromAST converts a literal into its go value, variables are looked
+// up from the given values
+func valueFromAST(v ast.Value, vars map[string]interface{}) interface{} {
+	switch o := v.(type) {
+	case *ast.Variable:
+		return vars[o.Name.Value]
+	case *ast.IntValue:
+		if i, err := strconv.Atoi(o.Value); err == nil {
+			return i
+		}
+		f, _ := strconv.ParseFloat(o.Value, 64)
+		return f
+	case *ast.FloatValue:
+		f, _ := strconv.ParseFloat(o.Value, 64)
+		return f
+	case *ast.StringValue:
+		return o.Value
+	case *ast.BooleanValue:
+		return o.Value
+	case *ast.EnumValue:
+		return o.Value
+	case *ast.ListValue:
+		list := make([]interface{}, len(o.Values))
+		for i, item := range o.Values {
+			list[i] = valueFromAST(item, vars)
+		}
+		return list
+	case *ast.ObjectValue:
+		obj := make(map[string]interface{}, len(o.Fields))
+		for _, field := range o.Fields {
+			obj[field.Name.Value] = valueFromAST(field.Value, vars)
+		}
+		return obj
+	}
+	return nil
+}
 
-	for _, set := range sets {
-		if len(set.Selections) >= 1 {
-			if field, ok := set.Selections[0].(*ast.Field); ok {
-				names = append(names, field.Name.Value)
-				for _, arg := range field.Arguments {
-					key := arg.Name.Value
-					switch o := arg.Value.GetValue().(type) {
-					case *ast.Name:
-						if d, ok := vars[o.Value]; ok {
-							args[key] = d
-						} else {
-							args[key] = nil
-						}
-					default:
-						args[key] = o
-					}
+// coerceValue converts a value to the argument type where go types differ,
+// json variables decode numbers to float64 and times to strings
+func coerceValue(typ graphql.Input, o interface{}) interface{} {
+	if typ == nil || o == nil {
+		return o
+	}
+	switch t := graphql.GetNullable(typ).(type) {
+	case *graphql.List:
+		if list, ok := o.([]interface{}); ok {
+			res := make([]interface{}, len(list))
+			for i, item := range list {
+				res[i] = coerceValue(t.OfType, item)
+			}
+			return res
+		}
+		return []interface{}{coerceValue(t.OfType, o)}
+	case *graphql.Scalar:
+		switch t {
+		case graphql.Int:
+			if f, ok := o.(float64); ok && f == math.Trunc(f) {
+				return int(f)
+			}
+		case graphql.Float:
+			if i, ok := o.(int); ok {
+				return float64(i)
+			}
+		case graphql.DateTime:
+			if s, ok := o.(string); ok {
+				if d, err := time.Parse(time.RFC3339, s); err == nil {
+					return d
 				}
 			}
 		}
 	}
-
-	return names, args
+	return o
 }
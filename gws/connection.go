@@ -3,9 +3,11 @@ package gws
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/websocket/outbound"
 )
 
 type connection struct {
@@ -21,8 +23,13 @@ type connection struct {
 	cache                CacheAdaptor
 	message              MessageAdaptor
 	logger               LoggerAdaptor
-	server               Service
+	server               *server
 	conn                 *websocket.Conn
+	protocol             string
+	initialised          chan struct{}
+	acknowledged         bool
+	queue                *outbound.Queue
+	bucket               *bucket
 }
 
 func (v *connection) MachineID() string {
@@ -45,6 +52,10 @@ func (v *connection) Connection() *websocket.Conn {
 	return v.conn
 }
 
+func (v *connection) Protocol() string {
+	return v.protocol
+}
+
 func (v *connection) Context() context.Context {
 	return v.ctx
 }
@@ -87,20 +98,29 @@ func (v *connection) Write(id string, o interface{}) error {
 	case *OperationMessage:
 		msg = d
 	default:
+		typ := gqlData
+		if v.protocol == ProtocolGraphQLTransportWS {
+			typ = gqlNext
+		}
 		msg = &OperationMessage{
-			Type:    gqlData,
+			Type:    typ,
 			Payload: o,
 		}
 	}
 	msg.ID = id
-	return v.conn.WriteMessage(websocket.TextMessage, msg.Marshal())
+	return v.write(msg.Marshal())
 }
 
 func (v *connection) Error(id string, errs interface{}) error {
 	var dat interface{}
-	switch errs.(type) {
-	case []error, error:
-		dat = errs
+	switch e := errs.(type) {
+	case []error:
+		dat = formatErrors(e)
+	case error:
+		dat = formatErrors([]error{e})[0]
+		if v.protocol == ProtocolGraphQLTransportWS {
+			dat = formatErrors([]error{e})
+		}
 	default:
 		return nil
 	}
@@ -109,7 +129,19 @@ func (v *connection) Error(id string, errs interface{}) error {
 		Type:    gqlError,
 		Payload: dat,
 	}
-	return v.conn.WriteMessage(websocket.TextMessage, o.Marshal())
+	return v.write(o.Marshal())
+}
+
+// write hands frames to the connection queue as the websocket connection
+// supports a single concurrent writer only
+func (v *connection) write(b []byte) error {
+	return v.queue.Write(websocket.TextMessage, b)
+}
+
+// closeWith terminates the connection with a protocol close code
+func (v *connection) closeWith(code int, reason string) error {
+	v.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+	return v.conn.Close()
 }
 
 func (v *connection) Close() error {
@@ -122,7 +154,10 @@ func (v *connection) BeforeOpen() {
 func (v *connection) AfterClose() {
 	// clean up subscriptions
 	for _, c := range v.Subscriptions().List() {
-		v.server.Subscriptions().Del(c)
-		v.Subscriptions().Del(c)
+		v.server.unsubscribe(v, c)
 	}
 }
+
+func (v *connection) limiter() *bucket {
+	return v.bucket
+}
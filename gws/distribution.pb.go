@@ -10,6 +10,7 @@ It is generated from these files:
 It has these top-level messages:
 	Distribution
 	Filter
+	Value
 	Condition
 */
 package gws
@@ -29,6 +30,39 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type Operator int32
+
+const (
+	Operator_EQ     Operator = 0
+	Operator_IN     Operator = 1
+	Operator_NOT_IN Operator = 2
+	Operator_RANGE  Operator = 3
+	Operator_PREFIX Operator = 4
+	Operator_ABSENT Operator = 5
+)
+
+var Operator_name = map[int32]string{
+	0: "EQ",
+	1: "IN",
+	2: "NOT_IN",
+	3: "RANGE",
+	4: "PREFIX",
+	5: "ABSENT",
+}
+var Operator_value = map[string]int32{
+	"EQ":     0,
+	"IN":     1,
+	"NOT_IN": 2,
+	"RANGE":  3,
+	"PREFIX": 4,
+	"ABSENT": 5,
+}
+
+func (x Operator) String() string {
+	return proto.EnumName(Operator_name, int32(x))
+}
+func (Operator) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
 type Distribution struct {
 	Field     string     `protobuf:"bytes,10,opt,name=field" json:"field,omitempty"`
 	Payload   []byte     `protobuf:"bytes,20,opt,name=payload,proto3" json:"payload,omitempty"`
@@ -78,7 +112,11 @@ func (m *Distribution) GetStrict() bool {
 }
 
 type Filter struct {
-	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Key      string   `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Operator Operator `protobuf:"varint,2,opt,name=operator,enum=gws.Operator" json:"operator,omitempty"`
+	Values   []*Value `protobuf:"bytes,3,rep,name=values" json:"values,omitempty"`
+	Min      *Value   `protobuf:"bytes,4,opt,name=min" json:"min,omitempty"`
+	Max      *Value   `protobuf:"bytes,5,opt,name=max" json:"max,omitempty"`
 	// Types that are valid to be assigned to ValOneof:
 	//	*Filter_StringValue
 	//	*Filter_IntValue
@@ -138,6 +176,34 @@ func (m *Filter) GetKey() string {
 	return ""
 }
 
+func (m *Filter) GetOperator() Operator {
+	if m != nil {
+		return m.Operator
+	}
+	return Operator_EQ
+}
+
+func (m *Filter) GetValues() []*Value {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *Filter) GetMin() *Value {
+	if m != nil {
+		return m.Min
+	}
+	return nil
+}
+
+func (m *Filter) GetMax() *Value {
+	if m != nil {
+		return m.Max
+	}
+	return nil
+}
+
 func (m *Filter) GetStringValue() string {
 	if x, ok := m.GetValOneof().(*Filter_StringValue); ok {
 		return x.StringValue
@@ -306,6 +372,227 @@ func _Filter_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
+type Value struct {
+	// Types that are valid to be assigned to ValOneof:
+	//	*Value_StringValue
+	//	*Value_IntValue
+	//	*Value_FloatValue
+	//	*Value_BoolValue
+	//	*Value_BytesValue
+	//	*Value_TimeValue
+	ValOneof isValue_ValOneof `protobuf_oneof:"val_oneof"`
+}
+
+func (m *Value) Reset()                    { *m = Value{} }
+func (m *Value) String() string            { return proto.CompactTextString(m) }
+func (*Value) ProtoMessage()               {}
+func (*Value) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+type isValue_ValOneof interface {
+	isValue_ValOneof()
+}
+
+type Value_StringValue struct {
+	StringValue string `protobuf:"bytes,10,opt,name=string_value,json=stringValue,oneof"`
+}
+type Value_IntValue struct {
+	IntValue int64 `protobuf:"varint,11,opt,name=int_value,json=intValue,oneof"`
+}
+type Value_FloatValue struct {
+	FloatValue float64 `protobuf:"fixed64,12,opt,name=float_value,json=floatValue,oneof"`
+}
+type Value_BoolValue struct {
+	BoolValue bool `protobuf:"varint,13,opt,name=bool_value,json=boolValue,oneof"`
+}
+type Value_BytesValue struct {
+	BytesValue []byte `protobuf:"bytes,14,opt,name=bytes_value,json=bytesValue,proto3,oneof"`
+}
+type Value_TimeValue struct {
+	TimeValue int64 `protobuf:"varint,15,opt,name=time_value,json=timeValue,oneof"`
+}
+
+func (*Value_StringValue) isValue_ValOneof() {}
+func (*Value_IntValue) isValue_ValOneof()    {}
+func (*Value_FloatValue) isValue_ValOneof()  {}
+func (*Value_BoolValue) isValue_ValOneof()   {}
+func (*Value_BytesValue) isValue_ValOneof()  {}
+func (*Value_TimeValue) isValue_ValOneof()   {}
+
+func (m *Value) GetValOneof() isValue_ValOneof {
+	if m != nil {
+		return m.ValOneof
+	}
+	return nil
+}
+
+func (m *Value) GetStringValue() string {
+	if x, ok := m.GetValOneof().(*Value_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (m *Value) GetIntValue() int64 {
+	if x, ok := m.GetValOneof().(*Value_IntValue); ok {
+		return x.IntValue
+	}
+	return 0
+}
+
+func (m *Value) GetFloatValue() float64 {
+	if x, ok := m.GetValOneof().(*Value_FloatValue); ok {
+		return x.FloatValue
+	}
+	return 0
+}
+
+func (m *Value) GetBoolValue() bool {
+	if x, ok := m.GetValOneof().(*Value_BoolValue); ok {
+		return x.BoolValue
+	}
+	return false
+}
+
+func (m *Value) GetBytesValue() []byte {
+	if x, ok := m.GetValOneof().(*Value_BytesValue); ok {
+		return x.BytesValue
+	}
+	return nil
+}
+
+func (m *Value) GetTimeValue() int64 {
+	if x, ok := m.GetValOneof().(*Value_TimeValue); ok {
+		return x.TimeValue
+	}
+	return 0
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*Value) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _Value_OneofMarshaler, _Value_OneofUnmarshaler, _Value_OneofSizer, []interface{}{
+		(*Value_StringValue)(nil),
+		(*Value_IntValue)(nil),
+		(*Value_FloatValue)(nil),
+		(*Value_BoolValue)(nil),
+		(*Value_BytesValue)(nil),
+		(*Value_TimeValue)(nil),
+	}
+}
+
+func _Value_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*Value)
+	// val_oneof
+	switch x := m.ValOneof.(type) {
+	case *Value_StringValue:
+		b.EncodeVarint(10<<3 | proto.WireBytes)
+		b.EncodeStringBytes(x.StringValue)
+	case *Value_IntValue:
+		b.EncodeVarint(11<<3 | proto.WireVarint)
+		b.EncodeVarint(uint64(x.IntValue))
+	case *Value_FloatValue:
+		b.EncodeVarint(12<<3 | proto.WireFixed64)
+		b.EncodeFixed64(math.Float64bits(x.FloatValue))
+	case *Value_BoolValue:
+		t := uint64(0)
+		if x.BoolValue {
+			t = 1
+		}
+		b.EncodeVarint(13<<3 | proto.WireVarint)
+		b.EncodeVarint(t)
+	case *Value_BytesValue:
+		b.EncodeVarint(14<<3 | proto.WireBytes)
+		b.EncodeRawBytes(x.BytesValue)
+	case *Value_TimeValue:
+		b.EncodeVarint(15<<3 | proto.WireVarint)
+		b.EncodeVarint(uint64(x.TimeValue))
+	case nil:
+	default:
+		return fmt.Errorf("Value.ValOneof has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _Value_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*Value)
+	switch tag {
+	case 10: // val_oneof.string_value
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeStringBytes()
+		m.ValOneof = &Value_StringValue{x}
+		return true, err
+	case 11: // val_oneof.int_value
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.ValOneof = &Value_IntValue{int64(x)}
+		return true, err
+	case 12: // val_oneof.float_value
+		if wire != proto.WireFixed64 {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeFixed64()
+		m.ValOneof = &Value_FloatValue{math.Float64frombits(x)}
+		return true, err
+	case 13: // val_oneof.bool_value
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.ValOneof = &Value_BoolValue{x != 0}
+		return true, err
+	case 14: // val_oneof.bytes_value
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeRawBytes(true)
+		m.ValOneof = &Value_BytesValue{x}
+		return true, err
+	case 15: // val_oneof.time_value
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.ValOneof = &Value_TimeValue{int64(x)}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _Value_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*Value)
+	// val_oneof
+	switch x := m.ValOneof.(type) {
+	case *Value_StringValue:
+		n += proto.SizeVarint(10<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(len(x.StringValue)))
+		n += len(x.StringValue)
+	case *Value_IntValue:
+		n += proto.SizeVarint(11<<3 | proto.WireVarint)
+		n += proto.SizeVarint(uint64(x.IntValue))
+	case *Value_FloatValue:
+		n += proto.SizeVarint(12<<3 | proto.WireFixed64)
+		n += 8
+	case *Value_BoolValue:
+		n += proto.SizeVarint(13<<3 | proto.WireVarint)
+		n += 1
+	case *Value_BytesValue:
+		n += proto.SizeVarint(14<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(len(x.BytesValue)))
+		n += len(x.BytesValue)
+	case *Value_TimeValue:
+		n += proto.SizeVarint(15<<3 | proto.WireVarint)
+		n += proto.SizeVarint(uint64(x.TimeValue))
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
 type Condition struct {
 	EqIDs  []int64  `protobuf:"varint,10,rep,packed,name=EqIDs" json:"EqIDs,omitempty"`
 	NeIDs  []int64  `protobuf:"varint,11,rep,packed,name=NeIDs" json:"NeIDs,omitempty"`
@@ -316,7 +603,7 @@ type Condition struct {
 func (m *Condition) Reset()                    { *m = Condition{} }
 func (m *Condition) String() string            { return proto.CompactTextString(m) }
 func (*Condition) ProtoMessage()               {}
-func (*Condition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*Condition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *Condition) GetEqIDs() []int64 {
 	if m != nil {
@@ -349,34 +636,44 @@ func (m *Condition) GetNeKeys() []string {
 func init() {
 	proto.RegisterType((*Distribution)(nil), "gws.Distribution")
 	proto.RegisterType((*Filter)(nil), "gws.Filter")
+	proto.RegisterType((*Value)(nil), "gws.Value")
 	proto.RegisterType((*Condition)(nil), "gws.Condition")
+	proto.RegisterEnum("gws.Operator", Operator_name, Operator_value)
 }
 
 func init() { proto.RegisterFile("distribution.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 357 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x92, 0x41, 0x6f, 0xda, 0x30,
-	0x14, 0xc7, 0x63, 0x45, 0x03, 0xfc, 0x9c, 0xb1, 0xc9, 0x62, 0x93, 0x2f, 0xdb, 0x3c, 0xa6, 0x49,
-	0x3e, 0x4c, 0x1c, 0xd8, 0x75, 0xa7, 0x15, 0xaa, 0x54, 0x95, 0x38, 0xf8, 0xd0, 0x2b, 0x4a, 0x88,
-	0x13, 0x59, 0x4d, 0x63, 0x20, 0x06, 0x94, 0xcf, 0xd4, 0x8f, 0xd7, 0x2f, 0x50, 0xd9, 0x4e, 0x68,
-	0x6f, 0xf9, 0xff, 0xde, 0x4f, 0x2f, 0xcf, 0xcf, 0x06, 0x5a, 0xe8, 0xd6, 0x1e, 0x75, 0x7e, 0xb2,
-	0xda, 0x34, 0x8b, 0xfd, 0xd1, 0x58, 0x43, 0xe3, 0xea, 0xd2, 0xce, 0x9f, 0x11, 0x24, 0xab, 0x77,
-	0x35, 0x3a, 0x83, 0x0f, 0xa5, 0x56, 0x75, 0xc1, 0x80, 0x23, 0x81, 0x65, 0x08, 0x94, 0xc1, 0x78,
-	0x9f, 0x75, 0xb5, 0xc9, 0x0a, 0x36, 0xe3, 0x48, 0x24, 0x72, 0x88, 0xf4, 0x37, 0x8c, 0x4b, 0x5d,
-	0x5b, 0x75, 0x6c, 0xd9, 0x77, 0x1e, 0x0b, 0xb2, 0x24, 0x8b, 0xea, 0xd2, 0x2e, 0x6e, 0x3d, 0x93,
-	0x43, 0x8d, 0xfe, 0x01, 0xbc, 0x33, 0x4d, 0xa1, 0xdd, 0x3f, 0xd8, 0x92, 0x23, 0x41, 0x96, 0x53,
-	0x2f, 0xde, 0x0c, 0x54, 0xbe, 0x09, 0xf4, 0x2b, 0x8c, 0xdc, 0x48, 0x3b, 0xcb, 0xfe, 0x71, 0x24,
-	0x26, 0xb2, 0x4f, 0xf3, 0x17, 0x04, 0xa3, 0xd0, 0x99, 0x7e, 0x86, 0xf8, 0x51, 0x75, 0x0c, 0xf9,
-	0x29, 0xdd, 0x27, 0xfd, 0x05, 0x89, 0xd3, 0x9a, 0x6a, 0x7b, 0xce, 0xea, 0x93, 0x0a, 0x07, 0x48,
-	0x23, 0x49, 0x02, 0x7d, 0x70, 0x90, 0x7e, 0x03, 0xac, 0x1b, 0xdb, 0x1b, 0x84, 0x23, 0x11, 0xa7,
-	0x91, 0x9c, 0xe8, 0xc6, 0x86, 0xf2, 0x4f, 0x20, 0x65, 0x6d, 0xb2, 0x41, 0x48, 0x38, 0x12, 0x28,
-	0x8d, 0x24, 0x78, 0x18, 0x94, 0x1f, 0x00, 0xb9, 0x31, 0x75, 0x6f, 0x7c, 0x74, 0xf3, 0xa5, 0x91,
-	0xc4, 0x8e, 0x5d, 0x7b, 0xe4, 0x9d, 0x55, 0x6d, 0x6f, 0x4c, 0xdd, 0xbe, 0x5c, 0x0f, 0x0f, 0xaf,
-	0x3d, 0xac, 0x7e, 0x52, 0xbd, 0xf1, 0xa9, 0x1f, 0x03, 0x3b, 0xe6, 0x85, 0xff, 0x04, 0xf0, 0x39,
-	0xab, 0xb7, 0xa6, 0x51, 0xa6, 0x9c, 0x57, 0x80, 0xaf, 0x5b, 0x72, 0xf7, 0xb3, 0x3e, 0xdc, 0xad,
-	0x5a, 0x06, 0x3c, 0x16, 0xb1, 0x0c, 0xc1, 0xd1, 0x8d, 0x72, 0x94, 0x04, 0xea, 0x83, 0x5b, 0xe3,
-	0xfa, 0x70, 0xaf, 0xba, 0x96, 0xcd, 0x78, 0x2c, 0xb0, 0xec, 0x93, 0xe3, 0x1b, 0xe5, 0xf9, 0x97,
-	0xc0, 0x43, 0xca, 0x47, 0xfe, 0x61, 0xfc, 0x7d, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x99, 0x86, 0xac,
-	0x31, 0x2e, 0x02, 0x00, 0x00,
+	// 484 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x93, 0xdd, 0x6e, 0xd3, 0x3e,
+	0x18, 0xc6, 0xeb, 0x7a, 0xc9, 0x9a, 0x37, 0x5d, 0xff, 0x91, 0xd5, 0x3f, 0xf2, 0x01, 0x1f, 0xa6,
+	0x08, 0xc9, 0x20, 0xd4, 0x83, 0x72, 0xca, 0xc9, 0xc6, 0x32, 0x1a, 0x21, 0x65, 0x60, 0x26, 0xc4,
+	0x59, 0x95, 0xae, 0x6e, 0x65, 0x91, 0xc5, 0x5d, 0xe2, 0x7d, 0xf4, 0x9a, 0xb8, 0x29, 0xae, 0x04,
+	0x21, 0xdb, 0x49, 0x41, 0x88, 0x2b, 0xe0, 0x28, 0x7e, 0x7e, 0xcf, 0x93, 0xd7, 0x6f, 0x9c, 0xd7,
+	0x40, 0x56, 0xaa, 0x31, 0xb5, 0x5a, 0xde, 0x18, 0xa5, 0xab, 0xe9, 0xb6, 0xd6, 0x46, 0x13, 0xbc,
+	0xb9, 0x6b, 0x26, 0xdf, 0x10, 0x0c, 0x4f, 0x7f, 0xf3, 0xc8, 0x18, 0x82, 0xb5, 0x92, 0xe5, 0x8a,
+	0x02, 0x43, 0x3c, 0x12, 0x5e, 0x10, 0x0a, 0x87, 0xdb, 0x62, 0x57, 0xea, 0x62, 0x45, 0xc7, 0x0c,
+	0xf1, 0xa1, 0xe8, 0x24, 0x79, 0x0e, 0x87, 0x6b, 0x55, 0x1a, 0x59, 0x37, 0xf4, 0x31, 0xc3, 0x3c,
+	0x9e, 0xc5, 0xd3, 0xcd, 0x5d, 0x33, 0x3d, 0x73, 0x4c, 0x74, 0x1e, 0x79, 0x05, 0xd1, 0xa5, 0xae,
+	0x56, 0xca, 0xee, 0x41, 0x67, 0x0c, 0xf1, 0x78, 0x36, 0x72, 0xc1, 0xb7, 0x1d, 0x15, 0xbf, 0x02,
+	0xe4, 0x01, 0x84, 0xb6, 0xa5, 0x4b, 0x43, 0xdf, 0x30, 0xc4, 0x07, 0xa2, 0x55, 0x93, 0x1f, 0x7d,
+	0x08, 0x7d, 0x65, 0x92, 0x00, 0xfe, 0x2a, 0x77, 0x14, 0xb9, 0x2e, 0xed, 0x92, 0xbc, 0x80, 0x81,
+	0xde, 0xca, 0xba, 0x30, 0xba, 0xa6, 0x7d, 0x86, 0xf8, 0x68, 0x76, 0xe4, 0x76, 0x38, 0x6f, 0xa1,
+	0xd8, 0xdb, 0x64, 0x02, 0xe1, 0x6d, 0x51, 0xde, 0xc8, 0x86, 0x62, 0xd7, 0x33, 0xb8, 0xe0, 0x67,
+	0x8b, 0x44, 0xeb, 0x90, 0x87, 0x80, 0xaf, 0x54, 0x45, 0x0f, 0x18, 0xfa, 0x23, 0x60, 0xb1, 0x73,
+	0x8b, 0x7b, 0x1a, 0xfc, 0xc5, 0x2d, 0xee, 0xc9, 0x33, 0x18, 0xda, 0x8e, 0xab, 0xcd, 0xc2, 0x15,
+	0xf3, 0x67, 0x39, 0xef, 0x89, 0xd8, 0x53, 0x97, 0x24, 0x8f, 0x20, 0x52, 0x95, 0x69, 0x13, 0x31,
+	0x43, 0x1c, 0xcf, 0x7b, 0x62, 0xa0, 0x2a, 0xe3, 0xed, 0xa7, 0x10, 0xaf, 0x4b, 0x5d, 0x74, 0x81,
+	0x21, 0x43, 0x1c, 0xcd, 0x7b, 0x02, 0x1c, 0xf4, 0x91, 0x27, 0x00, 0x4b, 0xad, 0xcb, 0x36, 0x71,
+	0x64, 0x8f, 0x6a, 0xde, 0x13, 0x91, 0x65, 0xfb, 0x1a, 0xcb, 0x9d, 0x91, 0x4d, 0x9b, 0x18, 0xd9,
+	0x5f, 0x67, 0x6b, 0x38, 0xb8, 0xaf, 0x61, 0xd4, 0x95, 0x6c, 0x13, 0xff, 0xb5, 0x6d, 0x44, 0x96,
+	0xb9, 0xc0, 0x49, 0x0c, 0xd1, 0x6d, 0x51, 0x2e, 0x74, 0x25, 0xf5, 0x7a, 0xf2, 0x1d, 0x41, 0xe0,
+	0xdf, 0xfb, 0x67, 0x3f, 0x71, 0x03, 0xd1, 0x7e, 0x26, 0xed, 0x6d, 0x48, 0xaf, 0xb3, 0xd3, 0x86,
+	0x02, 0xc3, 0x1c, 0x0b, 0x2f, 0x2c, 0xcd, 0xa5, 0xa5, 0xb1, 0xa7, 0x4e, 0xd8, 0xa1, 0x4d, 0xaf,
+	0xdf, 0xcb, 0x5d, 0x43, 0xc7, 0x0c, 0xf3, 0x48, 0xb4, 0xca, 0xf2, 0x5c, 0x3a, 0xfe, 0xbf, 0xe7,
+	0x5e, 0xbd, 0xcc, 0x60, 0xd0, 0x8d, 0x26, 0x09, 0xa1, 0x9f, 0x7e, 0x4c, 0x7a, 0xf6, 0x99, 0xe5,
+	0x09, 0x22, 0x00, 0x61, 0x7e, 0x7e, 0xb1, 0xc8, 0xf2, 0xa4, 0x4f, 0x22, 0x08, 0xc4, 0x71, 0xfe,
+	0x2e, 0x4d, 0xb0, 0xc5, 0x1f, 0x44, 0x7a, 0x96, 0x7d, 0x49, 0x0e, 0xec, 0xfa, 0xf8, 0xe4, 0x53,
+	0x9a, 0x5f, 0x24, 0xc1, 0x32, 0x74, 0x37, 0xfa, 0xf5, 0xcf, 0x01, 0x00, 0xc0, 0x75, 0xd9, 0x01,
+	0xe7, 0x03, 0x00, 0x00,
 }
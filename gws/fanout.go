@@ -0,0 +1,188 @@
+package gws
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync/atomic"
+
+	"github.com/graphql-go/graphql"
+	"github.com/vaniila/hyper/router"
+)
+
+// job executes one subscription query and delivers the result to every
+// subscription sharing it
+type job struct {
+	field   string
+	payload []byte
+	subs    []Subscription
+	initial bool
+}
+
+// fanout spreads subscription execution over sharded workers, a given
+// subscription always lands on the same shard so its events stay ordered
+type fanout struct {
+	server     *server
+	shards     []chan *job
+	done       chan struct{}
+	executions uint64
+	deliveries uint64
+}
+
+func newFanout(s *server, workers, size int) *fanout {
+	f := &fanout{
+		server: s,
+		shards: make([]chan *job, workers),
+		done:   make(chan struct{}),
+	}
+	for i := range f.shards {
+		f.shards[i] = make(chan *job, size)
+		go f.work(f.shards[i])
+	}
+	return f
+}
+
+func (v *fanout) work(ch chan *job) {
+	for {
+		select {
+		case <-v.done:
+			return
+		case j := <-ch:
+			v.run(j)
+		}
+	}
+}
+
+// dispatch queues a job on the shard owning the key, it blocks once the
+// shard is full so publishers feel the back pressure
+func (v *fanout) dispatch(key string, j *job) {
+	if len(v.shards) == 0 {
+		v.run(j)
+		return
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	select {
+	case v.shards[h.Sum32()%uint32(len(v.shards))] <- j:
+	case <-v.done:
+	}
+}
+
+func (v *fanout) run(j *job) {
+	defer func() {
+		if err := recover(); err != nil && v.server.logger != nil {
+			v.server.logger.Error(fmt.Sprintf("subscription fan-out panic: %v", err))
+		}
+	}()
+	subs := v.accept(j)
+	if len(subs) == 0 {
+		return
+	}
+	sub := subs[0]
+	parent := sub.
+		Connection().
+		Context().
+		Value(router.RequestContext).(router.Context)
+	child := parent.
+		Child()
+	params := graphql.Params{
+		Schema:         v.server.schema,
+		RequestString:  sub.Query(),
+		VariableValues: sub.Variables(),
+		OperationName:  sub.OperationName(),
+		Context:        child.Context(),
+		RootObject:     map[string]interface{}{"$subscription_payload$": j.payload},
+	}
+	result := graphql.Do(params)
+	atomic.AddUint64(&v.executions, 1)
+	payload := &DataMessagePayload{
+		Data:   result.Data,
+		Errors: ErrorsFromGraphQLErrors(result.Errors),
+	}
+	// a failing subscriber must not stop delivery to the others
+	for _, sub := range subs {
+		if err := sub.Connection().Write(sub.ID(), payload); err == nil {
+			atomic.AddUint64(&v.deliveries, 1)
+		}
+	}
+}
+
+// accept keeps the subscriptions allowed by the subscribe handler of the
+// field, the field resolver runs it when given the filter source
+func (v *fanout) accept(j *job) []Subscription {
+	typ := v.server.schema.SubscriptionType()
+	if typ == nil || j.initial {
+		return j.subs
+	}
+	def, ok := typ.Fields()[j.field]
+	if !ok || def.Resolve == nil {
+		return j.subs
+	}
+	var subs []Subscription
+	for _, sub := range j.subs {
+		if v.allow(def, sub, j.payload) {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+func (v *fanout) allow(def *graphql.FieldDefinition, sub Subscription, payload []byte) (ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			ok = false
+			if v.server.logger != nil {
+				v.server.logger.Error(fmt.Sprintf("subscription filter panic: %v", err))
+			}
+		}
+	}()
+	parent := sub.
+		Connection().
+		Context().
+		Value(router.RequestContext).(router.Context)
+	o, err := def.Resolve(graphql.ResolveParams{
+		Source:  map[string]interface{}{"$subscription_filter$": payload},
+		Args:    sub.Arguments(),
+		Context: parent.Child().Context(),
+	})
+	if b, isBool := o.(bool); err == nil && isBool {
+		return b
+	}
+	return true
+}
+
+func (v *fanout) stop() {
+	select {
+	case <-v.done:
+	default:
+		close(v.done)
+	}
+}
+
+func (v *fanout) stats() Stats {
+	s := Stats{
+		Workers:    len(v.shards),
+		Shards:     make([]int, len(v.shards)),
+		Executions: atomic.LoadUint64(&v.executions),
+		Deliveries: atomic.LoadUint64(&v.deliveries),
+	}
+	for i, ch := range v.shards {
+		s.Shards[i] = len(ch)
+		s.Queued += len(ch)
+	}
+	return s
+}
+
+// shareKey groups subscriptions which would produce the same result, those
+// of the same query, variables and connection identity, anonymous
+// connections never share since resolvers may read their request
+func shareKey(sub Subscription) string {
+	vars, _ := json.Marshal(sub.Variables())
+	c := sub.Connection()
+	id := c.Identity()
+	owner := fmt.Sprintf("%t:%d\x00%t:%s", id.HasID(), id.GetID(), id.HasKey(), id.GetKey())
+	if !id.HasID() && !id.HasKey() {
+		owner = fmt.Sprintf("anonymous:%s-%s", c.MachineID(), c.ProcessID())
+	}
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%s", sub.Query(), sub.OperationName(), vars, owner)
+}
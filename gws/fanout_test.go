@@ -0,0 +1,88 @@
+package gws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/vaniila/hyper/router"
+)
+
+// viewerSchema has a viewer subscription resolving to the identity of the
+// request it executes for
+func viewerSchema(t *testing.T) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: graphql.Fields{"ok": &graphql.Field{Type: graphql.Boolean}},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"viewer": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						src, _ := p.Source.(map[string]interface{})
+						if _, ok := src["$subscription_payload$"]; !ok {
+							return nil, nil
+						}
+						r := p.Context.Value(router.RequestContext).(router.Context)
+						switch id := r.Identity(); {
+						case id.HasID():
+							return fmt.Sprintf("id %d", id.GetID()), nil
+						case id.HasKey():
+							return "key " + id.GetKey(), nil
+						}
+						return "anonymous " + r.MachineID(), nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestFanoutSharesWithinIdentity(t *testing.T) {
+	s := New(Schema(viewerSchema(t)), Workers(0)).(*server)
+	tests := []struct {
+		machine string
+		id      int
+		key     string
+		want    string
+	}{
+		{"a", 1, "", "id 1"},
+		{"b", 1, "", "id 1"},
+		{"c", 2, "", "id 2"},
+		{"d", 0, "1", "key 1"},
+		{"e", 0, "", "anonymous e"},
+		{"f", 0, "", "anonymous f"},
+	}
+	var requests []*testRequest
+	for _, tt := range tests {
+		r := newTestRequest("", tt.id)
+		r.machine, r.identity.key = tt.machine, tt.key
+		c := &eventConnection{
+			connection: s.connection(r, nil),
+			stream:     r.stream,
+			done:       make(chan struct{}),
+		}
+		if _, errs := s.register(c, "1", &StartMessagePayload{Query: "subscription { viewer }"}); len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		requests = append(requests, r)
+	}
+	s.Subscribe(&Distribution{Field: "viewer", Payload: []byte("x")})
+	for i, tt := range tests {
+		want := fmt.Sprintf(`next {"data":{"viewer":"%s"},"errors":null}`, tt.want)
+		if events := requests[i].stream.list(); len(events) != 1 || events[0] != want {
+			t.Errorf("%s received %q, want %s", tt.machine, events, want)
+		}
+	}
+	// only the connections of the same identity share an execution
+	if stats := s.Stats(); stats.Executions != 5 || stats.Deliveries != 6 {
+		t.Errorf("%d executions and %d deliveries, want 5 and 6", stats.Executions, stats.Deliveries)
+	}
+}
@@ -0,0 +1,192 @@
+package gws
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// operand returns the comparison value of a filter
+func operand(f *Filter) interface{} {
+	switch o := f.GetValOneof().(type) {
+	case *Filter_StringValue:
+		return o.StringValue
+	case *Filter_IntValue:
+		return int(o.IntValue)
+	case *Filter_FloatValue:
+		return o.FloatValue
+	case *Filter_BoolValue:
+		return o.BoolValue
+	case *Filter_BytesValue:
+		return o.BytesValue
+	case *Filter_TimeValue:
+		return time.Unix(0, o.TimeValue)
+	}
+	return nil
+}
+
+// value returns the go representation of a filter value
+func value(v *Value) interface{} {
+	switch o := v.GetValOneof().(type) {
+	case *Value_StringValue:
+		return o.StringValue
+	case *Value_IntValue:
+		return int(o.IntValue)
+	case *Value_FloatValue:
+		return o.FloatValue
+	case *Value_BoolValue:
+		return o.BoolValue
+	case *Value_BytesValue:
+		return o.BytesValue
+	case *Value_TimeValue:
+		return time.Unix(0, o.TimeValue)
+	}
+	return nil
+}
+
+// matchFilter tests subscription arguments against a filter, every operator
+// but ABSENT requires the argument to be present
+func matchFilter(f *Filter, args map[string]interface{}) bool {
+	da, ok := args[f.GetKey()]
+	if f.GetOperator() == Operator_ABSENT {
+		return !ok || da == nil
+	}
+	if !ok {
+		return false
+	}
+	switch f.GetOperator() {
+	case Operator_IN:
+		for _, v := range f.GetValues() {
+			if equal(da, value(v)) {
+				return true
+			}
+		}
+		return false
+	case Operator_NOT_IN:
+		for _, v := range f.GetValues() {
+			if equal(da, value(v)) {
+				return false
+			}
+		}
+		return true
+	case Operator_RANGE:
+		if f.GetMin() == nil && f.GetMax() == nil {
+			return false
+		}
+		if f.GetMin() != nil {
+			if c, ok := compare(da, value(f.GetMin())); !ok || c < 0 {
+				return false
+			}
+		}
+		if f.GetMax() != nil {
+			if c, ok := compare(da, value(f.GetMax())); !ok || c > 0 {
+				return false
+			}
+		}
+		return true
+	case Operator_PREFIX:
+		switch o := operand(f).(type) {
+		case string:
+			s, ok := da.(string)
+			return ok && strings.HasPrefix(s, o)
+		case []byte:
+			b, ok := da.([]byte)
+			return ok && bytes.HasPrefix(b, o)
+		}
+		return false
+	}
+	// filters without a supported value only require the argument
+	if o := operand(f); o != nil {
+		return equal(da, o)
+	}
+	return true
+}
+
+// equal compares values of the same type only
+func equal(a, b interface{}) bool {
+	switch o := b.(type) {
+	case string:
+		s, ok := a.(string)
+		return ok && s == o
+	case int:
+		i, ok := a.(int)
+		return ok && i == o
+	case float64:
+		f, ok := a.(float64)
+		return ok && f == o
+	case bool:
+		v, ok := a.(bool)
+		return ok && v == o
+	case []byte:
+		v, ok := a.([]byte)
+		return ok && bytes.Equal(v, o)
+	case time.Time:
+		t, ok := a.(time.Time)
+		return ok && t.Equal(o)
+	}
+	return false
+}
+
+// compare orders numbers, strings and times, ints and floats compare
+// numerically with each other
+func compare(a, b interface{}) (int, bool) {
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+		return 0, false
+	}
+	switch o := b.(type) {
+	case string:
+		if s, ok := a.(string); ok {
+			return strings.Compare(s, o), true
+		}
+	case time.Time:
+		if t, ok := a.(time.Time); ok {
+			switch {
+			case t.Before(o):
+				return -1, true
+			case t.After(o):
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+func number(o interface{}) (float64, bool) {
+	switch v := o.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// strictMatch requires the subscription arguments to be exactly the keys
+// filtered on, filters for absent arguments are not counted
+func strictMatch(filters []*Filter, args map[string]interface{}) bool {
+	keys := make(map[string]struct{})
+	for _, f := range filters {
+		if f.GetOperator() != Operator_ABSENT {
+			keys[f.GetKey()] = struct{}{}
+		}
+	}
+	if len(keys) != len(args) {
+		return false
+	}
+	for k := range args {
+		if _, ok := keys[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
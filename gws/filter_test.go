@@ -0,0 +1,145 @@
+package gws
+
+import (
+	"testing"
+	"time"
+)
+
+// val wraps a go value into a filter value
+func val(o interface{}) *Value {
+	v := new(Value)
+	switch o := o.(type) {
+	case string:
+		v.ValOneof = &Value_StringValue{StringValue: o}
+	case int:
+		v.ValOneof = &Value_IntValue{IntValue: int64(o)}
+	case float64:
+		v.ValOneof = &Value_FloatValue{FloatValue: o}
+	case bool:
+		v.ValOneof = &Value_BoolValue{BoolValue: o}
+	case []byte:
+		v.ValOneof = &Value_BytesValue{BytesValue: o}
+	case time.Time:
+		v.ValOneof = &Value_TimeValue{TimeValue: o.UnixNano()}
+	}
+	return v
+}
+
+func values(key string, op Operator, vals ...interface{}) *Filter {
+	f := &Filter{Key: key, Operator: op}
+	for _, o := range vals {
+		f.Values = append(f.Values, val(o))
+	}
+	return f
+}
+
+func between(key string, min, max interface{}) *Filter {
+	f := &Filter{Key: key, Operator: Operator_RANGE}
+	if min != nil {
+		f.Min = val(min)
+	}
+	if max != nil {
+		f.Max = val(max)
+	}
+	return f
+}
+
+func prefix(key string, o interface{}) *Filter {
+	f := eq(key, "")
+	f.Operator = Operator_PREFIX
+	switch o := o.(type) {
+	case string:
+		f.ValOneof = &Filter_StringValue{StringValue: o}
+	case []byte:
+		f.ValOneof = &Filter_BytesValue{BytesValue: o}
+	case int:
+		f.ValOneof = &Filter_IntValue{IntValue: int64(o)}
+	}
+	return f
+}
+
+func TestMatchFilter(t *testing.T) {
+	at := time.Unix(100, 0)
+	args := map[string]interface{}{
+		"room":  "lobby",
+		"user":  2,
+		"score": 2.5,
+		"live":  true,
+		"raw":   []byte("abc"),
+		"at":    at,
+		"none":  nil,
+	}
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   bool
+	}{
+		{"eq", eq("room", "lobby"), true},
+		{"eq other", eq("room", "games"), false},
+		{"eq other type", eq("user", "2"), false},
+		{"eq missing", eq("missing", "lobby"), false},
+		{"eq without value", &Filter{Key: "room"}, true},
+		{"in", values("room", Operator_IN, "games", "lobby"), true},
+		{"in none", values("room", Operator_IN, "games"), false},
+		{"in empty", values("room", Operator_IN), false},
+		{"in int", values("user", Operator_IN, 1, 2), true},
+		{"in other type", values("user", Operator_IN, 2.0), false},
+		{"in bytes", values("raw", Operator_IN, []byte("abc")), true},
+		{"in time", values("at", Operator_IN, at), true},
+		{"in missing", values("missing", Operator_IN, "lobby"), false},
+		{"not in", values("room", Operator_NOT_IN, "games"), true},
+		{"not in listed", values("room", Operator_NOT_IN, "games", "lobby"), false},
+		{"not in empty", values("room", Operator_NOT_IN), true},
+		{"not in missing", values("missing", Operator_NOT_IN, "lobby"), false},
+		{"range", between("user", 1, 3), true},
+		{"range inclusive", between("user", 2, 2), true},
+		{"range below", between("user", 3, nil), false},
+		{"range above", between("user", nil, 1), false},
+		{"range open min", between("user", nil, 2), true},
+		{"range open max", between("score", 2.5, nil), true},
+		{"range int and float", between("score", 2, 3), true},
+		{"range float and int", between("user", 1.5, 2.5), true},
+		{"range string", between("room", "a", "m"), true},
+		{"range string above", between("room", "m", nil), false},
+		{"range time", between("at", at.Add(-time.Second), at), true},
+		{"range time after", between("at", at.Add(time.Second), nil), false},
+		{"range mixed types", between("room", 1, nil), false},
+		{"range bool", between("live", false, true), false},
+		{"range without bounds", between("user", nil, nil), false},
+		{"range missing", between("missing", 1, 3), false},
+		{"prefix", prefix("room", "lob"), true},
+		{"prefix whole", prefix("room", "lobby"), true},
+		{"prefix other", prefix("room", "gam"), false},
+		{"prefix bytes", prefix("raw", []byte("ab")), true},
+		{"prefix bytes of string", prefix("room", []byte("lob")), false},
+		{"prefix int", prefix("user", 2), false},
+		{"prefix missing", prefix("missing", "lob"), false},
+		{"absent", &Filter{Key: "missing", Operator: Operator_ABSENT}, true},
+		{"absent nil", &Filter{Key: "none", Operator: Operator_ABSENT}, true},
+		{"absent present", &Filter{Key: "room", Operator: Operator_ABSENT}, false},
+	}
+	for _, tt := range tests {
+		if got := matchFilter(tt.filter, args); got != tt.want {
+			t.Errorf("%s: matched %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStrictMatch(t *testing.T) {
+	args := map[string]interface{}{"room": "lobby", "user": 1}
+	tests := []struct {
+		name    string
+		filters []*Filter
+		want    bool
+	}{
+		{"exact keys", []*Filter{eq("room", "lobby"), values("user", Operator_IN, 1)}, true},
+		{"absent not counted", []*Filter{eq("room", "lobby"), eq("user", 1), {Key: "mode", Operator: Operator_ABSENT}}, true},
+		{"missing key", []*Filter{eq("room", "lobby")}, false},
+		{"other key", []*Filter{eq("room", "lobby"), eq("mode", 1)}, false},
+	}
+	for _, tt := range tests {
+		if got := strictMatch(tt.filters, args); got != tt.want {
+			t.Errorf("%s: matched %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -11,6 +11,14 @@ import (
 	"github.com/vaniila/hyper/logger"
 	"github.com/vaniila/hyper/message"
 	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/websocket/keepalive"
+	"github.com/vaniila/hyper/websocket/outbound"
+)
+
+// Websocket subprotocols handled by the server
+const (
+	ProtocolGraphQLWS          = "graphql-ws"
+	ProtocolGraphQLTransportWS = "graphql-transport-ws"
 )
 
 // HookFunc type
@@ -29,15 +37,28 @@ type Service interface {
 	Publish(*Distribution) error
 	Subscribe(*Distribution) error
 	Subscriptions() Store
+	Stats() Stats
 	Handle(router.Context, *websocket.Conn)
+	HandleSSE(router.Context)
 	Schema(graphql.Schema)
 	Adaptor() router.GQLSubscriptionAdaptor
 	Authorize(AuthorizeFunc)
+	KeepAlive(keepalive.Config)
+	Outbound(outbound.Config)
 	BeforeOpen(HookFunc)
 	AfterClose(HookFunc)
 	String() string
 }
 
+// Stats of the subscription fan-out
+type Stats struct {
+	Workers    int
+	Queued     int
+	Shards     []int
+	Executions uint64
+	Deliveries uint64
+}
+
 // Context interface
 type Context interface {
 	Identity() Identity
@@ -51,6 +72,7 @@ type Context interface {
 	Header() router.Header
 	Subscriptions() Subscriptions
 	Connection() *websocket.Conn
+	Protocol() string
 	Cache() CacheAdaptor
 	Message() MessageAdaptor
 	Logger() LoggerAdaptor
@@ -77,6 +99,7 @@ type Store interface {
 	Add(Subscription, ...bool) bool
 	Del(Subscription) bool
 	Get(...string) []Subscription
+	Match(*Distribution) []Subscription
 }
 
 // Subscriptions interface
@@ -133,9 +156,23 @@ func New(opts ...Option) Service {
 		message: o.Message,
 		logger:  o.Logger,
 		schema:  o.Schema,
-		conns:   make(map[string]Context),
-		tree:    &tree{state: make(map[string][]Subscription)},
+		init:    initTimeout,
+		limits: limits{
+			subscriptions: o.MaxSubscriptions,
+			depth:         o.MaxDepth,
+			complexity:    o.MaxComplexity,
+			rate:          o.RateLimit,
+			burst:         o.RateBurst,
+			clients:       &clients{buckets: make(map[string]*bucket)},
+		},
+		conns: make(map[string]Context),
+		tree: &tree{
+			state: make(map[string][]Subscription),
+			index: make(map[string][]Subscription),
+			keys:  make(map[Subscription][]string),
+		},
 	}
 	s.adaptor = &adaptor{s}
+	s.fanout = newFanout(s, o.Workers, o.QueueSize)
 	return s
 }
@@ -0,0 +1,86 @@
+package gws
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/vaniila/hyper/router"
+)
+
+// event payload a subscription receives once it is registered
+type initial struct {
+	field   string
+	payload []byte
+}
+
+// subscribe runs the subscribe hooks of every subscription field through
+// the field resolvers, an error rejects the subscription and payloads are
+// kept to be sent as initial events
+func (v *server) subscribe(sub *subscription) error {
+	for _, field := range sub.fields {
+		o, err := v.lifecycle(field, sub, "$subscription_start$")
+		if err != nil {
+			return err
+		}
+		if b, ok := o.([]byte); ok && b != nil {
+			sub.initial = append(sub.initial, initial{field, b})
+		}
+	}
+	return nil
+}
+
+// unsubscribe removes the subscription and runs the unsubscribe hooks
+func (v *server) unsubscribe(c Context, sub Subscription) bool {
+	indexed := v.tree.Del(sub)
+	registered := c.Subscriptions().Del(sub)
+	v.release(sub)
+	return indexed && registered
+}
+
+// release runs the unsubscribe hooks, also for subscriptions which were
+// rolled back after their subscribe hooks ran
+func (v *server) release(sub Subscription) {
+	for _, field := range sub.Fields() {
+		if _, err := v.lifecycle(field, sub, "$subscription_stop$"); err != nil && v.logger != nil {
+			v.logger.Error(fmt.Sprintf("subscription unsubscribe hook: %v", err))
+		}
+	}
+}
+
+// start delivers the initial events of a registered subscription, they go
+// through the shard of the subscription so later events follow them
+func (v *server) start(s Subscription) {
+	sub, ok := s.(*subscription)
+	if !ok {
+		return
+	}
+	for _, o := range sub.initial {
+		v.fanout.dispatch(shareKey(sub), &job{field: o.field, payload: o.payload, subs: []Subscription{sub}, initial: true})
+	}
+	sub.initial = nil
+}
+
+func (v *server) lifecycle(field string, sub Subscription, source string) (o interface{}, err error) {
+	typ := v.schema.SubscriptionType()
+	if typ == nil {
+		return nil, nil
+	}
+	def, ok := typ.Fields()[field]
+	if !ok || def.Resolve == nil {
+		return nil, nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			o, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+	parent := sub.
+		Connection().
+		Context().
+		Value(router.RequestContext).(router.Context)
+	return def.Resolve(graphql.ResolveParams{
+		Source:  map[string]interface{}{source: nil},
+		Args:    sub.Arguments(),
+		Context: parent.Child().Context(),
+	})
+}
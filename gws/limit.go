@@ -0,0 +1,140 @@
+package gws
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/vaniila/hyper/router"
+)
+
+var errRateLimit = errors.New("Too many operations, rate limit exceeded")
+
+// limits bound what a connection may subscribe to, zero values are unlimited
+type limits struct {
+	subscriptions int
+	depth         int
+	complexity    int
+	rate          float64
+	burst         int
+	clients       *clients
+}
+
+// clients holds the buckets of clients opening a connection per operation,
+// buckets which refilled completely are dropped on the next sweep
+type clients struct {
+	buckets map[string]*bucket
+	swept   time.Time
+	sync.Mutex
+}
+
+// check rejects subscriptions beyond the connection limit and queries which
+// are too deep or too complex
+func (v limits) check(c Context, doc *ast.Document, opname string) error {
+	if v.subscriptions > 0 && len(c.Subscriptions().List()) >= v.subscriptions {
+		return fmt.Errorf("Too many subscriptions, the limit is %d per connection", v.subscriptions)
+	}
+	if v.depth == 0 && v.complexity == 0 {
+		return nil
+	}
+	depth, complexity := queryCost(doc, opname)
+	if v.depth > 0 && depth > v.depth {
+		return fmt.Errorf("Query depth %d exceeds the limit of %d", depth, v.depth)
+	}
+	if v.complexity > 0 && complexity > v.complexity {
+		return fmt.Errorf("Query complexity %d exceeds the limit of %d", complexity, v.complexity)
+	}
+	return nil
+}
+
+func (v limits) bucket() *bucket {
+	if v.rate <= 0 {
+		return nil
+	}
+	burst := float64(v.burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &bucket{rate: v.rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// client returns the bucket shared by the connections of a client
+func (v limits) client(key string) *bucket {
+	if v.rate <= 0 || v.clients == nil {
+		return nil
+	}
+	v.clients.Lock()
+	defer v.clients.Unlock()
+	if now := time.Now(); now.Sub(v.clients.swept) > time.Minute {
+		for k, b := range v.clients.buckets {
+			if b.idle(now) {
+				delete(v.clients.buckets, k)
+			}
+		}
+		v.clients.swept = now
+	}
+	b, ok := v.clients.buckets[key]
+	if !ok {
+		b = v.bucket()
+		v.clients.buckets[key] = b
+	}
+	return b
+}
+
+// bucket is a token bucket refilled at rate tokens per second
+type bucket struct {
+	rate, burst, tokens float64
+	last                time.Time
+	sync.Mutex
+}
+
+func (v *bucket) take() bool {
+	v.Lock()
+	defer v.Unlock()
+	now := time.Now()
+	v.tokens += now.Sub(v.last).Seconds() * v.rate
+	if v.tokens > v.burst {
+		v.tokens = v.burst
+	}
+	v.last = now
+	if v.tokens < 1 {
+		return false
+	}
+	v.tokens--
+	return true
+}
+
+// idle tells whether the bucket would be full by now
+func (v *bucket) idle(now time.Time) bool {
+	v.Lock()
+	defer v.Unlock()
+	return v.tokens+now.Sub(v.last).Seconds()*v.rate >= v.burst
+}
+
+// limiter is implemented by connections holding a bucket
+type limiter interface {
+	limiter() *bucket
+}
+
+// allow takes a token for an inbound operation of the connection
+func allow(c Context) bool {
+	if l, ok := c.(limiter); ok {
+		if b := l.limiter(); b != nil {
+			return b.take()
+		}
+	}
+	return true
+}
+
+// clientKey identifies the client of a connection by identity, anonymous
+// clients by address
+func clientKey(c Context, r router.Context) string {
+	if id := c.Identity(); id != nil && id.HasID() {
+		return fmt.Sprintf("id:%d", id.GetID())
+	} else if id != nil && id.HasKey() {
+		return "key:" + id.GetKey()
+	}
+	return "ip:" + r.Client().IP()
+}
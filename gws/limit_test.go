@@ -0,0 +1,98 @@
+package gws
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// takes counts the tokens the bucket hands out until it runs dry
+func takes(b *bucket) int {
+	n := 0
+	for b.take() {
+		n++
+	}
+	return n
+}
+
+func TestBucketRefill(t *testing.T) {
+	b := limits{rate: 10, burst: 3}.bucket()
+	if n := takes(b); n != 3 {
+		t.Errorf("took %d tokens of a full bucket, want 3", n)
+	}
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		tokens  int
+	}{
+		{"not refilled", 50 * time.Millisecond, 0},
+		{"one token", 100 * time.Millisecond, 1},
+		{"two tokens", 250 * time.Millisecond, 2},
+		{"capped at the burst", time.Hour, 3},
+	}
+	for _, tt := range tests {
+		b.Lock()
+		b.tokens, b.last = 0, time.Now().Add(-tt.elapsed)
+		b.Unlock()
+		if n := takes(b); n != tt.tokens {
+			t.Errorf("%s: took %d tokens, want %d", tt.name, n, tt.tokens)
+		}
+	}
+	if b.idle(time.Now()) || !b.idle(time.Now().Add(time.Second)) {
+		t.Error("bucket is idle before it refilled completely")
+	}
+	if (limits{rate: 10}).bucket().burst != 1 {
+		t.Error("burst below one token")
+	}
+	if (limits{}).bucket() != nil {
+		t.Error("bucket without a rate")
+	}
+}
+
+func TestClientBuckets(t *testing.T) {
+	l := limits{rate: 1, burst: 1, clients: &clients{buckets: make(map[string]*bucket)}}
+	a := l.client("id:1")
+	if l.client("id:1") != a || l.client("id:2") == a {
+		t.Error("clients do not own one bucket each")
+	}
+	a.take()
+	l.client("id:2").take()
+	a.Lock()
+	a.last = time.Now().Add(-time.Hour)
+	a.Unlock()
+	// sweeps drop buckets which refilled completely
+	l.clients.swept = time.Now().Add(-2 * time.Minute)
+	l.client("id:3")
+	if _, ok := l.clients.buckets["id:1"]; ok {
+		t.Error("idle bucket kept after the sweep")
+	}
+	if _, ok := l.clients.buckets["id:2"]; !ok {
+		t.Error("busy bucket dropped by the sweep")
+	}
+	if (limits{clients: l.clients}).client("id:1") != nil {
+		t.Error("client bucket without a rate")
+	}
+}
+
+func TestHandleSSERateLimit(t *testing.T) {
+	s := New(Schema(newsSchema(t)), Workers(0), RateLimit(0.001, 1)).(*server)
+	tests := []struct {
+		name    string
+		id      int
+		limited bool
+	}{
+		{"first stream", 1, false},
+		{"second stream of the client", 1, true},
+		{"stream of another client", 2, false},
+		{"third stream of the client", 1, true},
+	}
+	for _, tt := range tests {
+		// invalid operations end the stream once the token is taken
+		r := newTestRequest("subscription { missing }", tt.id)
+		s.HandleSSE(r)
+		limited := r.status == http.StatusTooManyRequests
+		if limited != tt.limited || limited == (len(r.stream.list()) > 0) {
+			t.Errorf("%s: status %d and events %q, limited %v", tt.name, r.status, r.stream.list(), tt.limited)
+		}
+	}
+}
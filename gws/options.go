@@ -3,6 +3,7 @@ package gws
 import (
 	"crypto/rand"
 	"fmt"
+	"runtime"
 
 	"github.com/graphql-go/graphql"
 	"github.com/vaniila/hyper/cache"
@@ -33,6 +34,29 @@ type Options struct {
 
 	// logger
 	Logger logger.Service
+
+	// Workers executing subscription fan-out, zero runs it inline
+	Workers int
+
+	// QueueSize is the number of pending fan-out jobs per worker
+	QueueSize int
+
+	// MaxSubscriptions per connection, zero is unlimited
+	MaxSubscriptions int
+
+	// MaxDepth of subscription queries, zero is unlimited
+	MaxDepth int
+
+	// MaxComplexity of subscription queries counted in selected fields,
+	// zero is unlimited
+	MaxComplexity int
+
+	// RateLimit of subscribe operations per second and connection, event
+	// stream clients share one bucket, zero is unlimited
+	RateLimit float64
+
+	// RateBurst is the number of subscribe operations allowed at once
+	RateBurst int
 }
 
 func newID() string {
@@ -45,7 +69,9 @@ func newID() string {
 
 func newOptions(opts ...Option) Options {
 	opt := Options{
-		ID: newID(),
+		ID:        newID(),
+		Workers:   runtime.NumCPU(),
+		QueueSize: 1024,
 	}
 	for _, o := range opts {
 		o(&opt)
@@ -97,3 +123,47 @@ func Logger(l logger.Service) Option {
 		o.Logger = l
 	}
 }
+
+// Workers to set the number of fan-out workers
+func Workers(i int) Option {
+	return func(o *Options) {
+		o.Workers = i
+	}
+}
+
+// QueueSize to set the number of pending fan-out jobs per worker
+func QueueSize(i int) Option {
+	return func(o *Options) {
+		o.QueueSize = i
+	}
+}
+
+// MaxSubscriptions to limit subscriptions per connection
+func MaxSubscriptions(i int) Option {
+	return func(o *Options) {
+		o.MaxSubscriptions = i
+	}
+}
+
+// MaxDepth to limit the depth of subscription queries
+func MaxDepth(i int) Option {
+	return func(o *Options) {
+		o.MaxDepth = i
+	}
+}
+
+// MaxComplexity to limit the number of fields subscription queries select
+func MaxComplexity(i int) Option {
+	return func(o *Options) {
+		o.MaxComplexity = i
+	}
+}
+
+// RateLimit to limit subscribe operations per second and connection with
+// a token bucket holding burst operations
+func RateLimit(rate float64, burst int) Option {
+	return func(o *Options) {
+		o.RateLimit = rate
+		o.RateBurst = burst
+	}
+}
@@ -10,7 +10,7 @@ import (
 const (
 	gqlConnectionInit      = "connection_init"
 	gqlConnectionAck       = "connection_ack"
-	gqlConnectionKeepAlive = "keepalive"
+	gqlConnectionKeepAlive = "ka"
 	gqlConnectionError     = "connection_error"
 	gqlConnectionTerminate = "connection_terminate"
 	gqlSubscriptionData    = "subscription_data"
@@ -26,6 +26,24 @@ const (
 	gqlInvalid             = "<invalid>"
 )
 
+// Constants for graphql-transport-ws packet types
+const (
+	gqlPing      = "ping"
+	gqlPong      = "pong"
+	gqlSubscribe = "subscribe"
+	gqlNext      = "next"
+)
+
+// Close codes of the graphql-transport-ws protocol
+const (
+	closeInvalidMessage   = 4400
+	closeUnauthorized     = 4401
+	closeForbidden        = 4403
+	closeInitTimeout      = 4408
+	closeSubscriberExists = 4409
+	closeTooManyInit      = 4429
+)
+
 // InitMessagePayload defines the parameters of a connection
 // init message.
 type InitMessagePayload struct {
@@ -16,6 +16,8 @@ import (
 	"github.com/vaniila/hyper/logger"
 	"github.com/vaniila/hyper/message"
 	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/websocket/keepalive"
+	"github.com/vaniila/hyper/websocket/outbound"
 )
 
 type server struct {
@@ -25,9 +27,14 @@ type server struct {
 	message  message.Service
 	logger   logger.Service
 	schema   graphql.Schema
+	limits   limits
 	conns    map[string]Context
 	tree     Store
 	adaptor  router.GQLSubscriptionAdaptor
+	fanout   *fanout
+	alive    keepalive.Config
+	init     time.Duration
+	out      outbound.Config
 	hookauth AuthorizeFunc
 	hookbo   HookFunc
 	hookac   HookFunc
@@ -50,6 +57,7 @@ func (v *server) Start() error {
 
 func (v *server) Stop() error {
 	v.stop()
+	v.fanout.stop()
 	return nil
 }
 
@@ -101,8 +109,12 @@ func (v *server) Subscribe(d *Distribution) error {
 			}
 		}
 	}
+	var (
+		keys   []string
+		groups = make(map[string][]Subscription)
+	)
 subscriptions:
-	for _, sub := range v.tree.Get(d.Field) {
+	for _, sub := range v.tree.Match(d) {
 		if eqids != nil {
 			if !sub.Connection().Identity().HasID() {
 				continue
@@ -137,117 +149,39 @@ subscriptions:
 		}
 		args := sub.Arguments()
 		for _, filter := range d.Filters {
-			da, ok := args[filter.GetKey()]
-			if !ok {
+			if !matchFilter(filter, args) {
 				continue subscriptions
 			}
-			switch o := filter.GetValOneof().(type) {
-			case *Filter_StringValue:
-				s, ok := da.(string)
-				if !ok || s != o.StringValue {
-					continue subscriptions
-				}
-			case *Filter_IntValue:
-				i, ok := da.(int)
-				if !ok || i != int(o.IntValue) {
-					continue subscriptions
-				}
-			case *Filter_FloatValue:
-				f, ok := da.(float64)
-				if !ok || f != o.FloatValue {
-					continue subscriptions
-				}
-			case *Filter_BoolValue:
-				b, ok := da.(bool)
-				if !ok || b != o.BoolValue {
-					continue subscriptions
-				}
-			case *Filter_BytesValue:
-				b, ok := da.([]byte)
-				if !ok || len(b) != len(o.BytesValue) {
-					continue subscriptions
-				}
-				for i := range b {
-					z := b[i]
-					x := o.BytesValue[i]
-					if z != x {
-						continue subscriptions
-					}
-				}
-			case *Filter_TimeValue:
-				s, ok := da.(time.Time)
-				if !ok {
-					continue subscriptions
-				}
-				if t := time.Unix(0, o.TimeValue); !t.Equal(s) {
-					continue subscriptions
-				}
-			}
-		}
-		if d.Strict {
-			var fm = make(map[string]struct{})
-			if len(d.Filters) != len(args) {
-				continue subscriptions
-			}
-			for _, filter := range d.Filters {
-				if _, ok := fm[filter.GetKey()]; !ok {
-					fm[filter.GetKey()] = struct{}{}
-				}
-			}
-			for k := range args {
-				if _, ok := fm[k]; !ok {
-					continue subscriptions
-				}
-			}
 		}
-		parent := sub.
-			Connection().
-			Context().
-			Value(router.RequestContext).(router.Context)
-		child := parent.
-			Child()
-		params := graphql.Params{
-			Schema:         v.schema,
-			RequestString:  sub.Query(),
-			VariableValues: sub.Variables(),
-			OperationName:  sub.OperationName(),
-			Context:        child.Context(),
-			RootObject:     map[string]interface{}{"$subscription_payload$": d.Payload},
+		if d.Strict && !strictMatch(d.Filters, args) {
+			continue subscriptions
 		}
-		result := graphql.Do(params)
-		if err := sub.Connection().Write(sub.ID(), &DataMessagePayload{
-			Data:   result.Data,
-			Errors: ErrorsFromGraphQLErrors(result.Errors),
-		}); err != nil {
-			return err
+		key := shareKey(sub)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
 		}
+		groups[key] = append(groups[key], sub)
+	}
+	for _, key := range keys {
+		v.fanout.dispatch(key, &job{field: d.Field, payload: d.Payload, subs: groups[key]})
 	}
 	return nil
 }
 
+func (v *server) Stats() Stats {
+	return v.fanout.stats()
+}
+
 func (v *server) Subscriptions() Store {
 	return v.tree
 }
 
 func (v *server) Handle(r router.Context, n *websocket.Conn) {
 	u := fmt.Sprintf("%s-%s", r.MachineID(), r.ProcessID())
-	c := &connection{
-		machineID:     r.MachineID(),
-		processID:     r.ProcessID(),
-		identity:      r.Identity(),
-		subscriptions: &subscriptions{subs: make(map[string]Subscription, 0)},
-		ctx:           r.Context(),
-		req:           r.Req(),
-		res:           r.Res(),
-		client:        r.Client(),
-		cookie:        r.Cookie(),
-		header:        r.Header(),
-		cache:         v.cache,
-		message:       v.message,
-		logger:        v.logger,
-		server:        v,
-		conn:          n,
-	}
+	c := v.connection(r, n)
+	c.protocol = n.Subprotocol()
+	c.queue = outbound.Start(n, v.out)
+	defer c.queue.Stop()
 	v.Lock()
 	v.conns[u] = c
 	v.Unlock()
@@ -265,12 +199,57 @@ func (v *server) Handle(r router.Context, n *websocket.Conn) {
 		}
 		c.AfterClose()
 	}()
+	var tick func() error
+	if c.protocol != ProtocolGraphQLTransportWS {
+		tick = func() error {
+			return c.Write(gqlUnknown, &OperationMessage{Type: gqlConnectionKeepAlive})
+		}
+	}
+	monitor := keepalive.Start(n, v.alive, tick)
+	defer monitor.Stop()
+	if c.protocol == ProtocolGraphQLTransportWS {
+		timer := time.AfterFunc(v.init, func() {
+			select {
+			case <-c.initialised:
+			default:
+				c.closeWith(closeInitTimeout, "Connection initialisation timeout")
+			}
+		})
+		defer timer.Stop()
+	}
 	for {
 		mt, message, err := n.ReadMessage()
 		if err != nil {
 			break
 		}
-		v.Read(mt, message, c)
+		monitor.Touch()
+		if c.protocol == ProtocolGraphQLTransportWS {
+			v.readTransport(mt, message, c)
+		} else {
+			v.Read(mt, message, c)
+		}
+	}
+}
+
+func (v *server) connection(r router.Context, n *websocket.Conn) *connection {
+	return &connection{
+		machineID:     r.MachineID(),
+		processID:     r.ProcessID(),
+		identity:      r.Identity(),
+		subscriptions: &subscriptions{subs: make(map[string]Subscription, 0)},
+		ctx:           r.Context(),
+		req:           r.Req(),
+		res:           r.Res(),
+		client:        r.Client(),
+		cookie:        r.Cookie(),
+		header:        r.Header(),
+		cache:         v.cache,
+		message:       v.message,
+		logger:        v.logger,
+		server:        v,
+		conn:          n,
+		initialised:   make(chan struct{}),
+		bucket:        v.limits.bucket(),
 	}
 }
 
@@ -313,54 +292,19 @@ func (v *server) Read(mt int, message []byte, c Context) {
 				return
 			}
 
-			if c.Subscriptions().Has(msg.ID) {
-				c.Error(msg.ID, []error{errors.New("Cannot register subscription twice")})
+			if !allow(c) {
+				c.Error(msg.ID, errRateLimit)
 				return
 			}
 
-			sub := &subscription{
-				id:        msg.ID,
-				query:     data.Query,
-				opname:    data.OperationName,
-				variables: data.Variables,
-				args:      make(map[string]interface{}),
-				ctx:       c,
-			}
-
-			if errs := validateSubscription(sub); len(errs) > 0 {
+			sub, errs := v.register(c, msg.ID, data)
+			if len(errs) > 0 {
 				c.Error(msg.ID, errs)
 				return
 			}
 
-			doc, err := parser.Parse(parser.ParseParams{
-				Source: sub.query,
-			})
-			if err != nil {
-				c.Error(msg.ID, []error{err})
-				return
-			}
-
-			if validation := graphql.ValidateDocument(&v.schema, doc, nil); !validation.IsValid {
-				c.Error(msg.ID, ErrorsFromGraphQLErrors(validation.Errors))
-				return
-			}
-
-			sub.doc = doc
-
-			fields, args := getSubscriptionInfo(doc, data.Variables)
-			sub.fields = fields
-			sub.args = args
-
-			if !c.Subscriptions().Add(sub, true) {
-				c.Error(msg.ID, []error{errors.New("Unable to register subscription")})
-				return
-			}
-			if !v.tree.Add(sub) {
-				c.Error(msg.ID, []error{errors.New("Unable to register subscription")})
-				return
-			}
-
 			c.Write(msg.ID, &OperationMessage{Type: gqlSubscriptionSuccess})
+			v.start(sub)
 
 		// Handle all the stopping operations here
 		case gqlStop:
@@ -376,7 +320,7 @@ func (v *server) Read(mt int, message []byte, c Context) {
 			}
 
 			sub := c.Subscriptions().Get(msg.ID)
-			if !v.tree.Del(sub) || !c.Subscriptions().Del(sub) {
+			if !v.unsubscribe(c, sub) {
 				c.Error(msg.ID, []error{errors.New("Unable to deregister subscription")})
 				return
 			}
@@ -393,10 +337,80 @@ func (v *server) Read(mt int, message []byte, c Context) {
 	}
 }
 
+// register validates a subscription request and adds it to the connection
+// and the subscription tree
+func (v *server) register(c Context, id string, data *StartMessagePayload) (Subscription, []error) {
+
+	if c.Subscriptions().Has(id) {
+		return nil, []error{errors.New("Cannot register subscription twice")}
+	}
+
+	sub := &subscription{
+		id:        id,
+		query:     data.Query,
+		opname:    data.OperationName,
+		variables: data.Variables,
+		args:      make(map[string]interface{}),
+		ctx:       c,
+	}
+
+	if errs := validateSubscription(sub); len(errs) > 0 {
+		return nil, errs
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: sub.query,
+	})
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	if validation := graphql.ValidateDocument(&v.schema, doc, nil); !validation.IsValid {
+		return nil, ErrorsFromGraphQLErrors(validation.Errors)
+	}
+
+	if err := v.limits.check(c, doc, data.OperationName); err != nil {
+		return nil, []error{err}
+	}
+
+	sub.doc = doc
+
+	fields, args, err := getSubscriptionInfo(&v.schema, doc, data.OperationName, data.Variables)
+	if err != nil {
+		return nil, []error{err}
+	}
+	sub.fields = fields
+	sub.args = args
+
+	if err := v.subscribe(sub); err != nil {
+		return nil, []error{err}
+	}
+
+	if !c.Subscriptions().Add(sub, true) {
+		v.release(sub)
+		return nil, []error{errors.New("Unable to register subscription")}
+	}
+	if !v.tree.Add(sub) {
+		c.Subscriptions().Del(sub)
+		v.release(sub)
+		return nil, []error{errors.New("Unable to register subscription")}
+	}
+
+	return sub, nil
+}
+
 func (v *server) Authorize(f AuthorizeFunc) {
 	v.hookauth = f
 }
 
+func (v *server) KeepAlive(c keepalive.Config) {
+	v.alive = c
+}
+
+func (v *server) Outbound(c outbound.Config) {
+	v.out = c
+}
+
 func (v *server) BeforeOpen(f HookFunc) {
 	v.hookbo = f
 }
@@ -0,0 +1,186 @@
+package gws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/vaniila/hyper/router"
+)
+
+// Constants for server-sent event types
+const (
+	sseNext      = "next"
+	sseComplete  = "complete"
+	sseKeepAlive = 15 * time.Second
+)
+
+// eventConnection streams the results of a single subscription as
+// server-sent events instead of websocket frames
+type eventConnection struct {
+	*connection
+	stream router.EventStream
+	done   chan struct{}
+	closed bool
+	sync.RWMutex
+}
+
+func (v *eventConnection) send(e router.Event) error {
+	v.RLock()
+	defer v.RUnlock()
+	if v.closed || v.stream == nil {
+		return nil
+	}
+	return v.stream.Send(e)
+}
+
+func (v *eventConnection) Write(id string, o interface{}) error {
+	// protocol frames have no counterpart in the event stream
+	if _, ok := o.(*OperationMessage); ok {
+		return nil
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return v.send(router.Event{Event: sseNext, Data: b})
+}
+
+func (v *eventConnection) Error(id string, errs interface{}) error {
+	var list []error
+	switch e := errs.(type) {
+	case []error:
+		list = e
+	case error:
+		list = []error{e}
+	default:
+		return nil
+	}
+	return v.Write(id, &DataMessagePayload{Errors: formatErrors(list)})
+}
+
+func (v *eventConnection) Close() error {
+	v.Lock()
+	defer v.Unlock()
+	if !v.closed {
+		if v.stream != nil {
+			v.stream.Send(router.Event{Event: sseComplete})
+		}
+		v.closed = true
+		close(v.done)
+	}
+	return nil
+}
+
+// HandleSSE serves a single subscription over server-sent events, the
+// operation is read from the query string for GET and the JSON body otherwise.
+// Errors of the operation itself are sent as a next event followed by complete
+func (v *server) HandleSSE(r router.Context) {
+	data, err := ssePayload(r.Req())
+	if err != nil {
+		r.Status(http.StatusBadRequest).Json(&DataMessagePayload{Errors: formatErrors([]error{err})})
+		return
+	}
+	u := fmt.Sprintf("%s-%s", r.MachineID(), r.ProcessID())
+	c := &eventConnection{
+		connection: v.connection(r, nil),
+		done:       make(chan struct{}),
+	}
+	v.Lock()
+	v.conns[u] = c
+	v.Unlock()
+	if v.hookbo != nil {
+		v.hookbo(c)
+	}
+	c.BeforeOpen()
+	defer func() {
+		recover()
+		c.Close()
+		v.Lock()
+		delete(v.conns, u)
+		v.Unlock()
+		if v.hookac != nil {
+			v.hookac(c)
+		}
+		c.AfterClose()
+	}()
+	if fn := v.hookauth; fn != nil {
+		token := strings.TrimPrefix(r.Req().Header.Get("Authorization"), "Bearer ")
+		if err := fn(c, token); err != nil {
+			err = fmt.Errorf("Failed to authenticate user: %v", err)
+			r.Status(http.StatusUnauthorized).Json(&DataMessagePayload{Errors: formatErrors([]error{err})})
+			return
+		}
+	}
+	// every event stream is a new connection so clients share a bucket
+	c.bucket = v.limits.client(clientKey(c, r))
+	if !allow(c) {
+		r.Status(http.StatusTooManyRequests).Json(&DataMessagePayload{Errors: formatErrors([]error{errRateLimit})})
+		return
+	}
+	// the stream is opened first so events published as soon as the
+	// subscription is registered are not dropped
+	stream := r.SSE()
+	c.Lock()
+	c.stream = stream
+	c.Unlock()
+	sub, errs := v.register(c, newID(), data)
+	if len(errs) > 0 {
+		c.Error("", errs)
+		return
+	}
+	v.start(sub)
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stream.Done():
+			return
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := stream.Comment(gqlConnectionKeepAlive); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func ssePayload(req *http.Request) (*StartMessagePayload, error) {
+	data := new(StartMessagePayload)
+	switch req.Method {
+	case http.MethodGet:
+		q := req.URL.Query()
+		data.Query = q.Get("query")
+		data.OperationName = q.Get("operationName")
+		if s := q.Get("variables"); s != "" {
+			if err := json.Unmarshal([]byte(s), &data.Variables); err != nil {
+				return nil, errors.New("Invalid subscription variables")
+			}
+		}
+	default:
+		if err := json.NewDecoder(req.Body).Decode(data); err != nil {
+			return nil, errors.New("Invalid subscription payload")
+		}
+	}
+	return data, nil
+}
+
+// formatErrors keeps GraphQL errors intact and wraps plain errors so they
+// serialize with a message
+func formatErrors(errs []error) []error {
+	out := make([]error, len(errs))
+	for i, err := range errs {
+		if f, ok := err.(gqlerrors.FormattedError); ok {
+			out[i] = f
+		} else {
+			out[i] = gqlerrors.NewFormattedError(err.Error())
+		}
+	}
+	return out
+}
@@ -0,0 +1,170 @@
+package gws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/vaniila/hyper/router"
+)
+
+type testIdentity struct {
+	id  int
+	key string
+}
+
+func (v *testIdentity) HasID() bool     { return v.id != 0 }
+func (v *testIdentity) GetID() int      { return v.id }
+func (v *testIdentity) SetID(i int)     { v.id = i }
+func (v *testIdentity) HasKey() bool    { return v.key != "" }
+func (v *testIdentity) GetKey() string  { return v.key }
+func (v *testIdentity) SetKey(s string) { v.key = s }
+
+// requestContext lets testRequest embed the router context, whose Context
+// method would clash with the embedded field name
+type requestContext = router.Context
+
+// testRequest is the request context of a connection, it answers what the
+// server reads from the router
+type testRequest struct {
+	requestContext
+	machine  string
+	req      *http.Request
+	identity *testIdentity
+	ctx      context.Context
+	stream   *testStream
+	status   int
+}
+
+func newTestRequest(query string, id int) *testRequest {
+	r := &testRequest{
+		machine:  "machine",
+		req:      httptest.NewRequest("GET", "/?query="+url.QueryEscape(query), nil),
+		identity: &testIdentity{id: id},
+		stream:   &testStream{done: make(chan struct{})},
+	}
+	r.ctx = context.WithValue(context.Background(), router.RequestContext, r)
+	return r
+}
+
+func (v *testRequest) MachineID() string                 { return v.machine }
+func (v *testRequest) ProcessID() string                 { return v.machine }
+func (v *testRequest) Identity() router.Identity         { return v.identity }
+func (v *testRequest) Context() context.Context          { return v.ctx }
+func (v *testRequest) Req() *http.Request                { return v.req }
+func (v *testRequest) Res() http.ResponseWriter          { return nil }
+func (v *testRequest) Client() router.Client             { return nil }
+func (v *testRequest) Cookie() router.Cookie             { return nil }
+func (v *testRequest) Header() router.Header             { return nil }
+func (v *testRequest) Child() router.Context             { return v }
+func (v *testRequest) SSE() router.EventStream           { return v.stream }
+func (v *testRequest) Json(o interface{}) router.Context { return v }
+
+func (v *testRequest) Status(code int) router.Context {
+	v.status = code
+	return v
+}
+
+// testStream records the events sent to it
+type testStream struct {
+	events []string
+	done   chan struct{}
+	sync.Mutex
+}
+
+func (v *testStream) Send(e router.Event) error {
+	v.Lock()
+	defer v.Unlock()
+	v.events = append(v.events, e.Event+" "+string(e.Data))
+	return nil
+}
+
+func (v *testStream) Comment(string) error  { return nil }
+func (v *testStream) Done() <-chan struct{} { return v.done }
+
+func (v *testStream) list() []string {
+	v.Lock()
+	defer v.Unlock()
+	return append([]string(nil), v.events...)
+}
+
+// newsSchema has a news subscription resolving to the published payload
+func newsSchema(t *testing.T) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: graphql.Fields{"ok": &graphql.Field{Type: graphql.Boolean}},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"news": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						src, _ := p.Source.(map[string]interface{})
+						if b, ok := src["$subscription_payload$"].([]byte); ok {
+							return string(b), nil
+						}
+						return nil, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+// publishingTree publishes to every subscription as soon as it is indexed
+type publishingTree struct {
+	Store
+	server *server
+}
+
+func (v *publishingTree) Add(sub Subscription, enforce ...bool) bool {
+	ok := v.Store.Add(sub, enforce...)
+	v.server.Subscribe(&Distribution{Field: "news", Payload: []byte("first")})
+	return ok
+}
+
+func TestHandleSSEPublishAfterSubscribe(t *testing.T) {
+	s := New(Schema(newsSchema(t)), Workers(0)).(*server)
+	s.tree = &publishingTree{s.tree, s}
+	r := newTestRequest("subscription { news }", 1)
+	done := make(chan struct{})
+	go func() {
+		s.HandleSSE(r)
+		close(done)
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(r.stream.list()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(r.stream.done)
+	<-done
+	events := r.stream.list()
+	if len(events) != 2 || events[0] != `next {"data":{"news":"first"},"errors":null}` || events[1] != "complete " {
+		t.Errorf("events = %q", events)
+	}
+}
+
+func TestHandleSSEInvalidQuery(t *testing.T) {
+	s := New(Schema(newsSchema(t)), Workers(0)).(*server)
+	r := newTestRequest("subscription { missing }", 1)
+	s.HandleSSE(r)
+	events := r.stream.list()
+	if len(events) != 2 || !strings.HasPrefix(events[0], `next {"data":null,"errors":[`) || events[1] != "complete " {
+		t.Errorf("events = %q", events)
+	}
+	if len(s.tree.Match(&Distribution{Field: "news"})) != 0 {
+		t.Error("the failed subscription is still indexed")
+	}
+}
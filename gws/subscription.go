@@ -8,6 +8,7 @@ type subscription struct {
 	variables, args   map[string]interface{}
 	doc               *ast.Document
 	ctx               Context
+	initial           []initial
 }
 
 func (v *subscription) ID() string {
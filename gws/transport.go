@@ -0,0 +1,107 @@
+package gws
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// initTimeout is how long a graphql-transport-ws client may take to send
+// its connection_init message
+const initTimeout = 10 * time.Second
+
+// readTransport handles messages of the graphql-transport-ws protocol,
+// protocol violations close the connection with the matching close code
+func (v *server) readTransport(mt int, message []byte, c *connection) {
+
+	if mt != websocket.TextMessage {
+		c.closeWith(closeInvalidMessage, "Invalid message received")
+		return
+	}
+
+	raw := json.RawMessage{}
+	msg := OperationMessage{Payload: &raw}
+
+	if err := json.Unmarshal(message, &msg); err != nil {
+		c.closeWith(closeInvalidMessage, "Invalid message received")
+		return
+	}
+
+	switch msg.Type {
+
+	// Acknowledge the connection once the optional payload is authorized,
+	// the message may only be sent once
+	case gqlConnectionInit:
+
+		select {
+		case <-c.initialised:
+			c.closeWith(closeTooManyInit, "Too many initialisation requests")
+			return
+		default:
+			close(c.initialised)
+		}
+		data := new(InitMessagePayload)
+		if len(raw) > 0 && string(raw) != "null" {
+			if err := json.Unmarshal(raw, &data); err != nil {
+				c.closeWith(closeInvalidMessage, "Invalid connection_init payload")
+				return
+			}
+		}
+		if fn := v.hookauth; fn != nil {
+			if err := fn(c, data.AuthToken); err != nil {
+				c.closeWith(closeForbidden, "Forbidden")
+				return
+			}
+		}
+		c.acknowledged = true
+		c.Write(gqlUnknown, &OperationMessage{Type: gqlConnectionAck})
+
+	case gqlPing:
+		c.Write(gqlUnknown, &OperationMessage{Type: gqlPong})
+
+	case gqlPong:
+
+	case gqlSubscribe:
+
+		if !c.acknowledged {
+			c.closeWith(closeUnauthorized, "Unauthorized")
+			return
+		}
+		if id := strings.TrimSpace(msg.ID); len(id) == 0 {
+			c.closeWith(closeInvalidMessage, "Invalid subscribe message")
+			return
+		}
+		data := new(StartMessagePayload)
+		if err := json.Unmarshal(raw, &data); err != nil {
+			c.closeWith(closeInvalidMessage, "Invalid subscribe payload")
+			return
+		}
+		if c.Subscriptions().Has(msg.ID) {
+			c.closeWith(closeSubscriberExists, fmt.Sprintf("Subscriber for %s already exists", msg.ID))
+			return
+		}
+		if !allow(c) {
+			c.Error(msg.ID, errRateLimit)
+			return
+		}
+		sub, errs := v.register(c, msg.ID, data)
+		if len(errs) > 0 {
+			c.Error(msg.ID, errs)
+			return
+		}
+		v.start(sub)
+
+	// The client is no longer interested in the subscription
+	case gqlComplete:
+
+		if sub := c.Subscriptions().Get(msg.ID); sub != nil {
+			v.unsubscribe(c, sub)
+		}
+
+	default:
+		c.closeWith(closeInvalidMessage, "Invalid message received")
+	}
+}
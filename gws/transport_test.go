@@ -0,0 +1,144 @@
+package gws
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialTransport serves the server over a websocket speaking the
+// graphql-transport-ws protocol and returns the client end
+func dialTransport(t *testing.T, s *server) *websocket.Conn {
+	upgrader := websocket.Upgrader{Subprotocols: []string{ProtocolGraphQLTransportWS}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		s.Handle(newTestRequest("", 1), n)
+	}))
+	t.Cleanup(ts.Close)
+	dialer := websocket.Dialer{Subprotocols: []string{ProtocolGraphQLTransportWS}}
+	c, _, err := dialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	c.SetReadDeadline(time.Now().Add(2 * time.Second))
+	return c
+}
+
+// frames reads messages until the connection closes and returns them with
+// the close code
+func frames(c *websocket.Conn) ([]string, int) {
+	var list []string
+	for {
+		_, b, err := c.ReadMessage()
+		if err != nil {
+			var ce *websocket.CloseError
+			if errors.As(err, &ce) {
+				return list, ce.Code
+			}
+			return list, 0
+		}
+		list = append(list, string(b))
+	}
+}
+
+func TestTransportCloseCodes(t *testing.T) {
+	const (
+		init      = `{"type":"connection_init"}`
+		subscribe = `{"id":"1","type":"subscribe","payload":{"query":"subscription { news }"}}`
+	)
+	tests := []struct {
+		name     string
+		messages []string
+		binary   bool
+		auth     error
+		code     int
+	}{
+		{"invalid json", []string{`{`}, false, nil, closeInvalidMessage},
+		{"unknown type", []string{init, `{"type":"start"}`}, false, nil, closeInvalidMessage},
+		{"binary message", []string{init}, true, nil, closeInvalidMessage},
+		{"invalid init payload", []string{`{"type":"connection_init","payload":1}`}, false, nil, closeInvalidMessage},
+		{"subscribe without id", []string{init, `{"type":"subscribe","payload":{}}`}, false, nil, closeInvalidMessage},
+		{"subscribe before init", []string{subscribe}, false, nil, closeUnauthorized},
+		{"forbidden", []string{init}, false, errors.New("denied"), closeForbidden},
+		{"second init", []string{init, init}, false, nil, closeTooManyInit},
+		{"subscriber exists", []string{init, subscribe, subscribe}, false, nil, closeSubscriberExists},
+	}
+	for _, tt := range tests {
+		s := New(Schema(newsSchema(t)), Workers(0)).(*server)
+		if tt.auth != nil {
+			s.Authorize(func(Context, string) error { return tt.auth })
+		}
+		c := dialTransport(t, s)
+		for _, m := range tt.messages {
+			typ := websocket.TextMessage
+			if tt.binary {
+				typ = websocket.BinaryMessage
+			}
+			c.WriteMessage(typ, []byte(m))
+		}
+		if _, code := frames(c); code != tt.code {
+			t.Errorf("%s: closed with %d, want %d", tt.name, code, tt.code)
+		}
+	}
+}
+
+func TestTransportInitTimeout(t *testing.T) {
+	s := New(Schema(newsSchema(t)), Workers(0)).(*server)
+	s.init = 50 * time.Millisecond
+	c := dialTransport(t, s)
+	if _, code := frames(c); code != closeInitTimeout {
+		t.Errorf("closed with %d, want %d", code, closeInitTimeout)
+	}
+
+	// an acknowledged connection stays open past the timeout
+	c = dialTransport(t, s)
+	c.WriteMessage(websocket.TextMessage, []byte(`{"type":"connection_init"}`))
+	time.Sleep(100 * time.Millisecond)
+	c.WriteMessage(websocket.TextMessage, []byte(`{"type":"ping"}`))
+	for _, want := range []string{`"type":"connection_ack"`, `"type":"pong"`} {
+		if _, b, err := c.ReadMessage(); err != nil || !strings.Contains(string(b), want) {
+			t.Errorf("read %s %v, want %s", b, err, want)
+		}
+	}
+}
+
+func TestTransportHandshake(t *testing.T) {
+	s := New(Schema(newsSchema(t)), Workers(0)).(*server)
+	var token string
+	s.Authorize(func(c Context, s string) error {
+		token = s
+		return nil
+	})
+	c := dialTransport(t, s)
+	read := func(want string) {
+		t.Helper()
+		if _, b, err := c.ReadMessage(); err != nil || string(b) != want {
+			t.Fatalf("read %s %v, want %s", b, err, want)
+		}
+	}
+	c.WriteMessage(websocket.TextMessage, []byte(`{"type":"connection_init","payload":{"authToken":"secret"}}`))
+	read(`{"type":"connection_ack"}`)
+	if token != "secret" {
+		t.Errorf("authorized token %q, want secret", token)
+	}
+	c.WriteMessage(websocket.TextMessage, []byte(`{"id":"1","type":"subscribe","payload":{"query":"subscription { news }"}}`))
+	c.WriteMessage(websocket.TextMessage, []byte(`{"type":"ping"}`))
+	read(`{"type":"pong"}`)
+	s.Subscribe(&Distribution{Field: "news", Payload: []byte("hello")})
+	read(`{"id":"1","type":"next","payload":{"data":{"news":"hello"},"errors":null}}`)
+	c.WriteMessage(websocket.TextMessage, []byte(`{"id":"1","type":"complete"}`))
+	c.WriteMessage(websocket.TextMessage, []byte(`{"type":"ping"}`))
+	read(`{"type":"pong"}`)
+	if subs := s.tree.Match(&Distribution{Field: "news"}); len(subs) != 0 {
+		t.Errorf("%d subscriptions left after complete", len(subs))
+	}
+}
@@ -1,9 +1,18 @@
 package gws
 
-import "sync"
+import (
+	"fmt"
+	"sync"
+	"time"
+)
 
+// tree stores subscriptions by field, they are also indexed by argument
+// value so filtered distributions only visit likely subscribers, identities
+// may change during a connection and are left to the condition checks
 type tree struct {
 	state map[string][]Subscription
+	index map[string][]Subscription
+	keys  map[Subscription][]string
 	sync.RWMutex
 }
 
@@ -34,6 +43,11 @@ func (v *tree) Add(sub Subscription, enforce ...bool) bool {
 			} else {
 				v.state[field] = []Subscription{sub}
 			}
+			keys := indexKeys(field, sub)
+			for _, key := range keys {
+				v.index[key] = append(v.index[key], sub)
+			}
+			v.keys[sub] = append(v.keys[sub], keys...)
 		}
 		return true
 	}
@@ -47,15 +61,23 @@ func (v *tree) Del(sub Subscription) bool {
 		var count int
 		for _, field := range sub.Fields() {
 			if group, ok := v.state[field]; ok {
-				for i, o := range group {
-					if o == sub {
-						v.state[field][i] = v.state[field][len(v.state[field])-1]
-						v.state[field] = v.state[field][:len(v.state[field])-1]
-						count++
-					}
+				var n int
+				v.state[field], n = remove(group, sub)
+				count += n
+				if len(v.state[field]) == 0 {
+					delete(v.state, field)
+				}
+			}
+		}
+		for _, key := range v.keys[sub] {
+			if group, ok := v.index[key]; ok {
+				v.index[key], _ = remove(group, sub)
+				if len(v.index[key]) == 0 {
+					delete(v.index, key)
 				}
 			}
 		}
+		delete(v.keys, sub)
 		return count > 0
 	}
 	return false
@@ -84,3 +106,101 @@ func (v *tree) Get(fields ...string) []Subscription {
 	}
 	return subs
 }
+
+// Match returns the candidate subscriptions of a distribution, taken from the
+// most selective argument index, the caller still verifies every filter and
+// condition
+func (v *tree) Match(d *Distribution) []Subscription {
+	v.RLock()
+	defer v.RUnlock()
+	best, ok := v.state[d.Field]
+	if !ok {
+		return nil
+	}
+	for _, f := range d.Filters {
+		var operands []interface{}
+		switch f.GetOperator() {
+		case Operator_EQ:
+			operands = []interface{}{operand(f)}
+		case Operator_IN:
+			for _, val := range f.GetValues() {
+				operands = append(operands, value(val))
+			}
+		default:
+			continue
+		}
+		if group, ok := v.lookup(d.Field, f.GetKey(), operands); ok && len(group) < len(best) {
+			best = group
+		}
+	}
+	subs := make([]Subscription, len(best))
+	copy(subs, best)
+	return subs
+}
+
+// lookup unions the index entries of argument values, it fails for values
+// which cannot be indexed
+func (v *tree) lookup(field, name string, operands []interface{}) ([]Subscription, bool) {
+	var group []Subscription
+	seen := make(map[string]struct{}, len(operands))
+	for _, o := range operands {
+		val, ok := argumentValue(o)
+		if !ok {
+			return nil, false
+		}
+		key := argumentKey(field, name, val)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			group = append(group, v.index[key]...)
+		}
+	}
+	return group, true
+}
+
+func remove(group []Subscription, sub Subscription) ([]Subscription, int) {
+	var count int
+	for i := 0; i < len(group); i++ {
+		if group[i] == sub {
+			group[i] = group[len(group)-1]
+			group = group[:len(group)-1]
+			count++
+			i--
+		}
+	}
+	return group, count
+}
+
+// indexKeys lists the index entries of a subscription for a field
+func indexKeys(field string, sub Subscription) []string {
+	var keys []string
+	for name, arg := range sub.Arguments() {
+		if val, ok := argumentValue(arg); ok {
+			keys = append(keys, argumentKey(field, name, val))
+		}
+	}
+	return keys
+}
+
+func argumentKey(field, name, val string) string {
+	return fmt.Sprintf("%s\x00arg\x00%s\x00%s", field, name, val)
+}
+
+// argumentValue encodes an argument with its type so that only values
+// passing the filter comparison share an index entry
+func argumentValue(o interface{}) (string, bool) {
+	switch v := o.(type) {
+	case string:
+		return "s:" + v, true
+	case int:
+		return fmt.Sprintf("i:%d", v), true
+	case float64:
+		return fmt.Sprintf("f:%v", v), true
+	case bool:
+		return fmt.Sprintf("b:%t", v), true
+	case []byte:
+		return "y:" + string(v), true
+	case time.Time:
+		return fmt.Sprintf("t:%d", v.UnixNano()), true
+	}
+	return "", false
+}
@@ -0,0 +1,152 @@
+package gws
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSub struct {
+	Subscription
+	id     string
+	fields []string
+	args   map[string]interface{}
+}
+
+func (v *testSub) ID() string                        { return v.id }
+func (v *testSub) Fields() []string                  { return v.fields }
+func (v *testSub) Arguments() map[string]interface{} { return v.args }
+
+func newTree() *tree {
+	return &tree{
+		state: make(map[string][]Subscription),
+		index: make(map[string][]Subscription),
+		keys:  make(map[Subscription][]string),
+	}
+}
+
+func eq(key string, val interface{}) *Filter {
+	f := &Filter{Key: key, Operator: Operator_EQ}
+	switch o := val.(type) {
+	case string:
+		f.ValOneof = &Filter_StringValue{StringValue: o}
+	case int:
+		f.ValOneof = &Filter_IntValue{IntValue: int64(o)}
+	case float64:
+		f.ValOneof = &Filter_FloatValue{FloatValue: o}
+	case bool:
+		f.ValOneof = &Filter_BoolValue{BoolValue: o}
+	case time.Time:
+		f.ValOneof = &Filter_TimeValue{TimeValue: o.UnixNano()}
+	}
+	return f
+}
+
+func in(key string, vals ...string) *Filter {
+	f := &Filter{Key: key, Operator: Operator_IN}
+	for _, s := range vals {
+		f.Values = append(f.Values, &Value{ValOneof: &Value_StringValue{StringValue: s}})
+	}
+	return f
+}
+
+// matched keeps the ids of the subscriptions passing every filter
+func matched(subs []Subscription, d *Distribution) string {
+	var ids []string
+	for _, sub := range subs {
+		ok := true
+		for _, f := range d.Filters {
+			if !matchFilter(f, sub.Arguments()) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			ids = append(ids, sub.ID())
+		}
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestTreeMatch(t *testing.T) {
+	at := time.Unix(100, 0)
+	subs := []Subscription{
+		&testSub{id: "a", fields: []string{"message"}, args: map[string]interface{}{"room": "lobby"}},
+		&testSub{id: "b", fields: []string{"message"}, args: map[string]interface{}{"room": "lobby", "user": 1}},
+		&testSub{id: "c", fields: []string{"message"}, args: map[string]interface{}{"room": "games", "user": 2}},
+		&testSub{id: "d", fields: []string{"message"}, args: map[string]interface{}{"room": 1}},
+		&testSub{id: "e", fields: []string{"message"}, args: map[string]interface{}{"user": 1.0}},
+		&testSub{id: "f", fields: []string{"message"}, args: map[string]interface{}{}},
+		&testSub{id: "g", fields: []string{"message", "typing"}, args: map[string]interface{}{"room": "lobby", "live": true}},
+		&testSub{id: "h", fields: []string{"message"}, args: map[string]interface{}{"room": []string{"lobby"}}},
+		&testSub{id: "i", fields: []string{"message"}, args: map[string]interface{}{"since": at}},
+		&testSub{id: "j", fields: []string{"typing"}, args: map[string]interface{}{"room": "games"}},
+	}
+	tests := []struct {
+		name       string
+		d          *Distribution
+		want       string
+		candidates int
+	}{
+		{"unfiltered", &Distribution{Field: "message"}, "a,b,c,d,e,f,g,h,i", 9},
+		{"unknown field", &Distribution{Field: "unknown", Filters: []*Filter{eq("room", "lobby")}}, "", 0},
+		{"string", &Distribution{Field: "message", Filters: []*Filter{eq("room", "lobby")}}, "a,b,g", 3},
+		{"int not string", &Distribution{Field: "message", Filters: []*Filter{eq("room", 1)}}, "d", 1},
+		{"int not float", &Distribution{Field: "message", Filters: []*Filter{eq("user", 1)}}, "b", 1},
+		{"float not int", &Distribution{Field: "message", Filters: []*Filter{eq("user", 1.0)}}, "e", 1},
+		{"bool", &Distribution{Field: "message", Filters: []*Filter{eq("live", true)}}, "g", 1},
+		{"time", &Distribution{Field: "message", Filters: []*Filter{eq("since", at)}}, "i", 1},
+		{"no match", &Distribution{Field: "message", Filters: []*Filter{eq("room", "none")}}, "", 0},
+		{"most selective", &Distribution{Field: "message", Filters: []*Filter{eq("room", "lobby"), eq("user", 1)}}, "b", 1},
+		{"in", &Distribution{Field: "message", Filters: []*Filter{in("room", "lobby", "games", "lobby")}}, "a,b,c,g", 4},
+		{"other field", &Distribution{Field: "typing", Filters: []*Filter{eq("room", "games")}}, "j", 1},
+		{"not indexed", &Distribution{Field: "message", Filters: []*Filter{{Key: "room", Operator: Operator_PREFIX, ValOneof: &Filter_StringValue{StringValue: "lob"}}}}, "a,b,g", 9},
+		{"absent", &Distribution{Field: "message", Filters: []*Filter{{Key: "room", Operator: Operator_ABSENT}}}, "e,f,i", 9},
+	}
+	tr := newTree()
+	for _, sub := range subs {
+		tr.Add(sub)
+	}
+	for _, tt := range tests {
+		candidates := tr.Match(tt.d)
+		if got := matched(candidates, tt.d); got != tt.want {
+			t.Errorf("%s: matched %q, want %q", tt.name, got, tt.want)
+		}
+		if got := matched(tr.Get(tt.d.Field), tt.d); got != tt.want {
+			t.Errorf("%s: linear filtering matched %q, want %q", tt.name, got, tt.want)
+		}
+		if len(candidates) != tt.candidates {
+			t.Errorf("%s: %d candidates, want %d", tt.name, len(candidates), tt.candidates)
+		}
+	}
+}
+
+func TestTreeDel(t *testing.T) {
+	a := &testSub{id: "a", fields: []string{"message", "typing"}, args: map[string]interface{}{"room": "lobby"}}
+	b := &testSub{id: "b", fields: []string{"message"}, args: map[string]interface{}{"room": "lobby"}}
+	tr := newTree()
+	tr.Add(a)
+	tr.Add(b)
+	if tr.Add(a) {
+		t.Error("subscription added twice")
+	}
+	if !tr.Del(a) {
+		t.Fatal("subscription was not deleted")
+	}
+	if tr.Del(a) {
+		t.Error("subscription deleted twice")
+	}
+	d := &Distribution{Field: "message", Filters: []*Filter{eq("room", "lobby")}}
+	if got := matched(tr.Match(d), d); got != "b" {
+		t.Errorf("matched %q after delete, want %q", got, "b")
+	}
+	if subs := tr.Get("typing"); len(subs) != 0 {
+		t.Errorf("typing still has %d subscriptions", len(subs))
+	}
+	tr.Del(b)
+	if len(tr.state) != 0 || len(tr.index) != 0 || len(tr.keys) != 0 {
+		t.Errorf("tree not empty: %d fields, %d index entries, %d keys", len(tr.state), len(tr.index), len(tr.keys))
+	}
+}
@@ -9,14 +9,16 @@ import (
 func New(opts ...Option) *Hyper {
 	o := newOptions(opts...)
 	w := websocket.New(
-		websocket.ID(o.ID),
-		websocket.Sync(o.Sync),
-		websocket.GQLSubscription(o.GQLSubscription),
-		websocket.Cache(o.Cache),
-		websocket.Message(o.Message),
-		websocket.Router(o.Router),
-		websocket.Logger(o.Logger),
-		websocket.EnableCompression(true),
+		append([]websocket.Option{
+			websocket.ID(o.ID),
+			websocket.Sync(o.Sync),
+			websocket.GQLSubscription(o.GQLSubscription),
+			websocket.Cache(o.Cache),
+			websocket.Message(o.Message),
+			websocket.Router(o.Router),
+			websocket.Logger(o.Logger),
+			websocket.EnableCompression(true),
+		}, o.Websocket...)...,
 	)
 	e := engine.New(
 		engine.ID(o.ID),
@@ -31,6 +33,10 @@ func New(opts ...Option) *Hyper {
 		engine.Websocket(w),
 		engine.TraceID(o.TraceID),
 		engine.EnableCompression(o.EnableCompression),
+		engine.EnableSwagger(o.EnableSwagger),
+		engine.ValidateResponses(o.ValidateResponses),
+		engine.SwaggerPath(o.SwaggerPath),
+		engine.Swagger(o.Swagger...),
 		engine.EnableCORS(o.EnableCORS),
 		engine.AllowedOrigins(o.AllowedOrigins),
 		engine.AllowOriginFunc(o.AllowOriginFunc),
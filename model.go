@@ -1,10 +1,18 @@
 package hyper
 
-import "github.com/vaniila/hyper/router"
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+
+	"github.com/vaniila/hyper/router"
+)
 
 type model struct {
-	code int
-	hash string
+	code      int
+	hash      string
+	structure interface{}
+	schema    *router.Schema
 }
 
 func (v *model) Code() int {
@@ -15,7 +23,26 @@ func (v *model) Hash() string {
 	return v.hash
 }
 
+func (v *model) Structure() interface{} {
+	return v.structure
+}
+
+func (v *model) Schema() *router.Schema {
+	return v.schema
+}
+
 // Model func
 func Model(code int, response interface{}) router.Model {
-	return &model{}
+	m := &model{
+		code:      code,
+		structure: response,
+		schema:    router.SchemaOf(response),
+	}
+	if response != nil {
+		t := reflect.TypeOf(response)
+		h := fnv.New64a()
+		h.Write([]byte(t.PkgPath() + "." + t.String()))
+		m.hash = fmt.Sprintf("%x", h.Sum64())
+	}
+	return m
 }
@@ -12,7 +12,9 @@ import (
 	"github.com/vaniila/hyper/logger"
 	"github.com/vaniila/hyper/message"
 	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/swagger"
 	"github.com/vaniila/hyper/sync"
+	"github.com/vaniila/hyper/websocket"
 )
 
 // Option func
@@ -61,6 +63,22 @@ type Options struct {
 	// EnableCompression to enable gzip compression
 	EnableCompression bool
 
+	// EnableSwagger to serve the OpenAPI document and swagger ui
+	EnableSwagger bool
+
+	// ValidateResponses checks entities rendered by Json and Render against
+	// the route model of the response status
+	ValidateResponses bool
+
+	// SwaggerPath is the path the OpenAPI document and swagger ui are served from
+	SwaggerPath string
+
+	// Swagger document options
+	Swagger []swagger.Option
+
+	// Websocket server options
+	Websocket []websocket.Option
+
 	// EnableCORS to attach cors handler to http server
 	EnableCORS bool
 
@@ -114,9 +132,10 @@ func newID() string {
 
 func newOptions(opts ...Option) Options {
 	opt := Options{
-		ID:       newID(),
-		Addr:     ":0",
-		Protocol: engine.HTTP,
+		ID:          newID(),
+		Addr:        ":0",
+		Protocol:    engine.HTTP,
+		SwaggerPath: "/swagger",
 	}
 	for _, o := range opts {
 		o(&opt)
@@ -263,6 +282,36 @@ func EnableCompression(b bool) Option {
 	}
 }
 
+// ValidateResponses to check rendered entities against route models
+func ValidateResponses(b bool) Option {
+	return func(o *Options) {
+		o.ValidateResponses = b
+	}
+}
+
+// Swagger to serve the OpenAPI document and swagger ui
+func Swagger(opts ...swagger.Option) Option {
+	return func(o *Options) {
+		o.EnableSwagger = true
+		o.Swagger = append(o.Swagger, opts...)
+	}
+}
+
+// Websocket to set websocket server options such as keepalive and timeouts
+func Websocket(opts ...websocket.Option) Option {
+	return func(o *Options) {
+		o.Websocket = append(o.Websocket, opts...)
+	}
+}
+
+// SwaggerPath to set the path of the OpenAPI document and swagger ui
+func SwaggerPath(s string) Option {
+	return func(o *Options) {
+		o.EnableSwagger = true
+		o.SwaggerPath = s
+	}
+}
+
 // AllowedOrigins to add allowed origins for CORS
 func AllowedOrigins(a []string) Option {
 	return func(o *Options) {
@@ -2,6 +2,7 @@ package hyper
 
 import (
 	"log"
+	"regexp"
 
 	"github.com/vaniila/hyper/router"
 )
@@ -17,6 +18,12 @@ type param struct {
 	deps          []router.Param
 	oneof         []router.Param
 	require       bool
+	multiple      bool
+	min, max      *float64
+	minlen        *int
+	maxlen        *int
+	pattern       *regexp.Regexp
+	enum          []string
 }
 
 type paramconfig struct {
@@ -53,6 +60,48 @@ func (v *param) Require(b bool) router.Param {
 	return v
 }
 
+func (v *param) Multiple(b bool) router.Param {
+	if b && v.typ == router.ParamParam {
+		log.Fatalf("cannot accept multiple values for %v field, path parameters only hold a single value", v.name)
+	}
+	v.multiple = b
+	return v
+}
+
+func (v *param) Min(f float64) router.Param {
+	v.min = &f
+	return v
+}
+
+func (v *param) Max(f float64) router.Param {
+	v.max = &f
+	return v
+}
+
+func (v *param) MinLength(i int) router.Param {
+	v.minlen = &i
+	return v
+}
+
+func (v *param) MaxLength(i int) router.Param {
+	v.maxlen = &i
+	return v
+}
+
+func (v *param) Pattern(s string) router.Param {
+	r, err := regexp.Compile(s)
+	if err != nil {
+		log.Fatalf("cannot compile pattern for %v field: %v", v.name, err)
+	}
+	v.pattern = r
+	return v
+}
+
+func (v *param) Enum(vs ...string) router.Param {
+	v.enum = vs
+	return v
+}
+
 func (v *param) DependsOn(deps ...router.Param) router.Param {
 	v.deps = deps
 	return v
@@ -98,6 +147,34 @@ func (v *paramconfig) Require() bool {
 	return v.require
 }
 
+func (v *paramconfig) Multiple() bool {
+	return v.multiple
+}
+
+func (v *paramconfig) Min() *float64 {
+	return v.min
+}
+
+func (v *paramconfig) Max() *float64 {
+	return v.max
+}
+
+func (v *paramconfig) MinLength() *int {
+	return v.minlen
+}
+
+func (v *paramconfig) MaxLength() *int {
+	return v.maxlen
+}
+
+func (v *paramconfig) Pattern() *regexp.Regexp {
+	return v.pattern
+}
+
+func (v *paramconfig) Enum() []string {
+	return v.enum
+}
+
 func (v *paramconfig) DependsOn() []router.Param {
 	return v.deps
 }
@@ -93,6 +93,15 @@ func (v *config) Middlewares() HandlerFuncs {
 	return v.middleware
 }
 
-func (v *config) Model(code int) interface{} {
+func (v *config) Models() []Model {
+	return v.models
+}
+
+func (v *config) Model(code int) Model {
+	for _, model := range v.models {
+		if model.Code() == code {
+			return model
+		}
+	}
 	return nil
 }
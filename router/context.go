@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"time"
 
@@ -57,6 +58,7 @@ type Context interface {
 	Param(s string) (Value, error)
 	Query(s string) (Value, error)
 	Body(s string) (Value, error)
+	Bind(interface{}) error
 	File(s string) []byte
 	StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span
 	Tracer() opentracing.Tracer
@@ -67,10 +69,31 @@ type Context interface {
 	Write(b []byte) Context
 	Error(error) Context
 	Json(o interface{}) Context
+	Render(o interface{}) Context
+	Redirect(code int, url string) Context
+	ServeFile(path string) Context
+	Attachment(name string, r io.Reader) Context
+	Stream(r io.Reader) Context
+	SSE() EventStream
 	Status(code int) Context
 	Child() Context
 }
 
+// Event for server-sent events
+type Event struct {
+	ID    string
+	Event string
+	Data  []byte
+	Retry time.Duration
+}
+
+// EventStream interface
+type EventStream interface {
+	Send(Event) error
+	Comment(string) error
+	Done() <-chan struct{}
+}
+
 // Identity interface
 type Identity interface {
 	HasID() bool
@@ -115,6 +138,27 @@ type GQLEvent interface {
 	Strict() bool
 }
 
+// GQLFilterOperator to match subscription arguments
+type GQLFilterOperator int
+
+// Subscription filter operators
+const (
+	FilterIn GQLFilterOperator = iota + 1
+	FilterNotIn
+	FilterRange
+	FilterPrefix
+	FilterAbsent
+)
+
+// GQLFilter matches subscription arguments with an operator, plain event
+// filter values are matched for equality
+type GQLFilter struct {
+	Operator GQLFilterOperator
+	Values   []interface{}
+	Min      interface{}
+	Max      interface{}
+}
+
 // GQLSubscriptionAdaptor interface
 type GQLSubscriptionAdaptor interface {
 	Emit(GQLEvent) error
@@ -149,6 +193,18 @@ type Value interface {
 	MustF64() float64
 	MustBool() bool
 	MustTime() time.Time
+	Vals() [][]byte
+	Parsed() interface{}
+	MustStrings() []string
+	MustInts() []int
+	MustI32s() []int32
+	MustI64s() []int64
+	MustU32s() []uint32
+	MustU64s() []uint64
+	MustF32s() []float32
+	MustF64s() []float64
+	MustBools() []bool
+	MustTimes() []time.Time
 	String() string
 }
 
@@ -204,3 +260,6 @@ type Hardware interface {
 	Brand() string
 	Model() string
 }
+
+// Encoder serializes a response entity for a media type
+type Encoder func(interface{}) ([]byte, error)
@@ -2,12 +2,15 @@ package router
 
 import (
 	"encoding/json"
+	"log"
 	"math"
 	"regexp"
 	"strconv"
+	"sync"
 	"time"
 )
 
+// Parameter formats
 const (
 	Any int = iota
 	Text
@@ -54,8 +57,65 @@ var validations = map[int]func([]byte) (interface{}, bool){
 	DateTimeUnix:    isDateTimeUnix,
 }
 
+var names = map[int]string{
+	Any:             "any",
+	Text:            "text",
+	Int:             "int",
+	I32:             "i32",
+	I64:             "i64",
+	U32:             "u32",
+	U64:             "u64",
+	F32:             "f32",
+	F64:             "f64",
+	Bool:            "bool",
+	Lat:             "lat",
+	Lon:             "lon",
+	Json:            "json",
+	Email:           "email",
+	URL:             "url",
+	UUID:            "uuid",
+	File:            "file",
+	DateTimeRFC822:  "datetime-rfc822",
+	DateTimeRFC3339: "datetime-rfc3339",
+	DateTimeUnix:    "datetime-unix",
+}
+
+var (
+	formats sync.RWMutex
+	nextID  = DateTimeUnix + 1
+)
+
+// FormatParser parses raw input, reporting whether it is valid
+type FormatParser func([]byte) (interface{}, bool)
+
+// RegisterFormat adds a custom parameter format and returns its id
+func RegisterFormat(name string, parser FormatParser) int {
+	formats.Lock()
+	defer formats.Unlock()
+	for id, n := range names {
+		if n == name {
+			log.Fatalf("parameter format %s has already been registered as %d", name, id)
+		}
+	}
+	id := nextID
+	nextID++
+	validations[id] = parser
+	names[id] = name
+	return id
+}
+
+// FormatName returns the name of a parameter format
+func FormatName(typ int) string {
+	formats.RLock()
+	defer formats.RUnlock()
+	return names[typ]
+}
+
+// Val parses raw input with the parameter format
 func Val(typ int, v []byte) (interface{}, bool) {
+	formats.RLock()
 	validation, ok := validations[typ]
+	formats.RUnlock()
 	if !ok {
 		return v, true
 	}
@@ -0,0 +1,207 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/vaniila/hyper/fault"
+)
+
+var (
+	typeTime       = reflect.TypeOf(time.Time{})
+	typeRawMessage = reflect.TypeOf(json.RawMessage{})
+	typeBytes      = reflect.TypeOf([]byte{})
+)
+
+// SchemaOf reflects on the response structure to build its schema
+func SchemaOf(o interface{}) *Schema {
+	if o == nil {
+		return nil
+	}
+	return reflectSchema(reflect.TypeOf(o), make(map[reflect.Type]bool))
+}
+
+func reflectSchema(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+	var s *Schema
+	switch {
+	case t == typeTime:
+		s = &Schema{Type: "string", Format: "date-time"}
+	case t == typeRawMessage:
+		s = &Schema{}
+	case t == typeBytes:
+		s = &Schema{Type: "string", Format: "byte"}
+	default:
+		switch t.Kind() {
+		case reflect.Bool:
+			s = &Schema{Type: "boolean"}
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+			s = &Schema{Type: "integer", Format: "int32"}
+		case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+			s = &Schema{Type: "integer", Format: "int64"}
+		case reflect.Float32:
+			s = &Schema{Type: "number", Format: "float"}
+		case reflect.Float64:
+			s = &Schema{Type: "number", Format: "double"}
+		case reflect.String:
+			s = &Schema{Type: "string"}
+		case reflect.Slice, reflect.Array:
+			s = &Schema{Type: "array", Items: reflectSchema(t.Elem(), seen)}
+		case reflect.Map:
+			s = &Schema{Type: "object", AdditionalProperties: reflectSchema(t.Elem(), seen)}
+		case reflect.Struct:
+			// recursive types are described once, nested references become plain objects
+			if seen[t] {
+				s = &Schema{Type: "object"}
+				break
+			}
+			seen[t] = true
+			s = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			reflectFields(s, t, seen)
+			delete(seen, t)
+		default:
+			s = &Schema{}
+		}
+	}
+	s.Nullable = nullable
+	return s
+}
+
+func reflectFields(s *Schema, t reflect.Type, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+		// embedded structs without a json name are flattened into the parent
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				reflectFields(s, ft, seen)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		prop := reflectSchema(field.Type, seen)
+		if strings.Contains(opts, "string") {
+			switch prop.Type {
+			case "integer", "number", "boolean":
+				prop = &Schema{Type: "string", Nullable: prop.Nullable}
+			}
+		}
+		s.Properties[name] = prop
+		if !strings.Contains(opts, "omitempty") && !prop.Nullable {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// Validate checks the value against the schema once encoded as json
+func (v *Schema) Validate(o interface{}) error {
+	var (
+		b, err = json.Marshal(o)
+		val    interface{}
+	)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &val); err != nil {
+		return err
+	}
+	causes := v.validate("", val, nil)
+	if len(causes) == 0 {
+		return nil
+	}
+	return fault.
+		New("Invalid Response Entity").
+		SetStatus(http.StatusInternalServerError).
+		AddCause(causes...)
+}
+
+func (v *Schema) validate(path string, o interface{}, causes []fault.Cause) []fault.Cause {
+	invalid := func() []fault.Cause {
+		return append(causes, fault.For(fault.Invalid).SetResource("response").SetField(path))
+	}
+	if o == nil {
+		// nil slices and maps are encoded as null by encoding/json
+		if v.Nullable || v.Type == "" || v.Type == "array" || v.AdditionalProperties != nil {
+			return causes
+		}
+		return invalid()
+	}
+	switch v.Type {
+	case "boolean":
+		if _, ok := o.(bool); !ok {
+			return invalid()
+		}
+	case "integer":
+		if f, ok := o.(float64); !ok || f != float64(int64(f)) {
+			return invalid()
+		}
+	case "number":
+		if _, ok := o.(float64); !ok {
+			return invalid()
+		}
+	case "string":
+		if _, ok := o.(string); !ok {
+			return invalid()
+		}
+	case "array":
+		a, ok := o.([]interface{})
+		if !ok {
+			return invalid()
+		}
+		if v.Items != nil {
+			for i, item := range a {
+				causes = v.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, causes)
+			}
+		}
+	case "object":
+		m, ok := o.(map[string]interface{})
+		if !ok {
+			return invalid()
+		}
+		for _, name := range v.Required {
+			if _, ok := m[name]; !ok {
+				causes = append(causes, fault.For(fault.MissingField).SetResource("response").SetField(fieldPath(path, name)))
+			}
+		}
+		for name, item := range m {
+			switch prop, ok := v.Properties[name]; {
+			case ok:
+				causes = prop.validate(fieldPath(path, name), item, causes)
+			case v.AdditionalProperties != nil:
+				causes = v.AdditionalProperties.validate(fieldPath(path, name), item, causes)
+			}
+		}
+	}
+	return causes
+}
+
+func fieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
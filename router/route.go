@@ -46,12 +46,6 @@ var warningNoBodyAllowed = `
 "Route [%s] %s does not accept any request body parameter [%v]"
 `
 
-// response struct
-type model struct {
-	code      int
-	structure interface{}
-}
-
 func (v *router) buildValueIndexes(ps []Param) {
 	for _, param := range ps {
 		conf := param.Config()
@@ -262,7 +256,27 @@ func (v *router) HTTP(b bool) Route {
 	return v
 }
 
+func (v *router) modelIndex(code int) int {
+	for i, m := range v.models {
+		if m.Code() == code {
+			return i
+		}
+	}
+	return -1
+}
+
 func (v *router) Models(ms ...Model) Route {
+	for _, m := range ms {
+		if m == nil {
+			continue
+		}
+		switch i := v.modelIndex(m.Code()); {
+		case i >= 0:
+			v.models[i] = m
+		default:
+			v.models = append(v.models, m)
+		}
+	}
 	return v
 }
 
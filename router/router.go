@@ -1,5 +1,7 @@
 package router
 
+import "regexp"
+
 // Service interface
 type Service interface {
 	Start() error
@@ -67,7 +69,8 @@ type RouteConfig interface {
 	Handler() HandlerFunc
 	Catch() HandlerFunc
 	Middlewares() HandlerFuncs
-	Model(int) interface{}
+	Models() []Model
+	Model(int) Model
 }
 
 // Param interface
@@ -78,6 +81,13 @@ type Param interface {
 	Doc(string) Param
 	Default([]byte) Param
 	Require(bool) Param
+	Multiple(bool) Param
+	Min(float64) Param
+	Max(float64) Param
+	MinLength(int) Param
+	MaxLength(int) Param
+	Pattern(string) Param
+	Enum(...string) Param
 	DependsOn(...Param) Param
 	Config() ParamConfig
 }
@@ -92,6 +102,13 @@ type ParamConfig interface {
 	Doc() string
 	Default() []byte
 	Require() bool
+	Multiple() bool
+	Min() *float64
+	Max() *float64
+	MinLength() *int
+	MaxLength() *int
+	Pattern() *regexp.Regexp
+	Enum() []string
 	DependsOn() []Param
 	OneOf() []Param
 }
@@ -100,6 +117,8 @@ type ParamConfig interface {
 type Model interface {
 	Code() int
 	Hash() string
+	Structure() interface{}
+	Schema() *Schema
 }
 
 // New creates engine server
@@ -0,0 +1,59 @@
+package router
+
+import "math"
+
+// Schema describes a parameter or response value in OpenAPI terms
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	DependsOn            []string           `json:"x-depends-on,omitempty"`
+}
+
+func bound(f float64) *float64 {
+	return &f
+}
+
+var schemas = map[int]Schema{
+	Any:             {Type: "string"},
+	Text:            {Type: "string"},
+	Int:             {Type: "integer", Format: "int64"},
+	I32:             {Type: "integer", Format: "int32"},
+	I64:             {Type: "integer", Format: "int64"},
+	U32:             {Type: "integer", Format: "int64", Minimum: bound(0), Maximum: bound(math.MaxUint32)},
+	U64:             {Type: "integer", Format: "int64", Minimum: bound(0)},
+	F32:             {Type: "number", Format: "float"},
+	F64:             {Type: "number", Format: "double"},
+	Bool:            {Type: "boolean"},
+	Lat:             {Type: "number", Format: "double", Minimum: bound(-90), Maximum: bound(90)},
+	Lon:             {Type: "number", Format: "double", Minimum: bound(-180), Maximum: bound(180)},
+	Json:            {Type: "string", Format: "json"},
+	Email:           {Type: "string", Format: "email"},
+	URL:             {Type: "string", Format: "uri"},
+	UUID:            {Type: "string", Format: "uuid"},
+	File:            {Type: "string", Format: "binary"},
+	DateTimeRFC822:  {Type: "string", Format: "date-time-rfc822"},
+	DateTimeRFC3339: {Type: "string", Format: "date-time"},
+	DateTimeUnix:    {Type: "integer", Format: "int64", Description: "unix timestamp in milliseconds"},
+}
+
+// FormatSchema returns the schema of a parameter format
+func FormatSchema(typ int) *Schema {
+	schema, ok := schemas[typ]
+	if !ok {
+		schema = Schema{Type: "string", Format: FormatName(typ)}
+	}
+	return &schema
+}
@@ -0,0 +1,116 @@
+package swagger
+
+import (
+	"encoding/json"
+
+	"github.com/vaniila/hyper/router"
+)
+
+// Document is the OpenAPI 3 root object
+type Document struct {
+	OpenAPI string               `json:"openapi"`
+	Info    *Info                `json:"info"`
+	Servers []*Server            `json:"servers,omitempty"`
+	Tags    []*Tag               `json:"tags,omitempty"`
+	Paths   map[string]*PathItem `json:"paths"`
+}
+
+// Info object
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server object
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag object
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem object
+type PathItem struct {
+	Get     *Operation `json:"get,omitempty"`
+	Put     *Operation `json:"put,omitempty"`
+	Post    *Operation `json:"post,omitempty"`
+	Delete  *Operation `json:"delete,omitempty"`
+	Options *Operation `json:"options,omitempty"`
+	Head    *Operation `json:"head,omitempty"`
+	Patch   *Operation `json:"patch,omitempty"`
+}
+
+// Operation object
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	OneOf       [][]string           `json:"x-one-of,omitempty"`
+}
+
+// Parameter object
+type Parameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *router.Schema `json:"schema,omitempty"`
+	DependsOn   []string       `json:"x-depends-on,omitempty"`
+}
+
+// RequestBody object
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+// MediaType object
+type MediaType struct {
+	Schema *router.Schema `json:"schema,omitempty"`
+}
+
+// Response object
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+func (v *PathItem) set(method string, op *Operation) {
+	switch method {
+	case "GET":
+		v.Get = op
+	case "PUT":
+		v.Put = op
+	case "POST":
+		v.Post = op
+	case "DELETE":
+		v.Delete = op
+	case "OPTIONS":
+		v.Options = op
+	case "HEAD":
+		v.Head = op
+	case "PATCH":
+		v.Patch = op
+	}
+}
+
+// JSON returns the document in json format
+func (v *Document) JSON() []byte {
+	b, _ := json.MarshalIndent(v, "", "  ")
+	return b
+}
+
+// YAML returns the document in yaml format
+func (v *Document) YAML() []byte {
+	b, _ := json.Marshal(v)
+	return toYAML(b)
+}
@@ -0,0 +1,73 @@
+package swagger
+
+import "strings"
+
+// Option func
+type Option func(*Options)
+
+// Options is the document generator options
+type Options struct {
+
+	// API title
+	Title string
+
+	// API version
+	Version string
+
+	// API description
+	Description string
+
+	// Server urls the API is reachable from
+	Servers []string
+
+	// Base url of the swagger ui assets
+	Assets string
+}
+
+func newOptions(opts ...Option) Options {
+	opt := Options{
+		Title:   "Hyper API",
+		Version: "1.0.0",
+		Assets:  "https://unpkg.com/swagger-ui-dist@3",
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+	return opt
+}
+
+// Title to set API title
+func Title(s string) Option {
+	return func(o *Options) {
+		o.Title = s
+	}
+}
+
+// Version to set API version
+func Version(s string) Option {
+	return func(o *Options) {
+		o.Version = s
+	}
+}
+
+// Description to set API description
+func Description(s string) Option {
+	return func(o *Options) {
+		o.Description = s
+	}
+}
+
+// Servers to add server urls
+func Servers(a ...string) Option {
+	return func(o *Options) {
+		o.Servers = append(o.Servers, a...)
+	}
+}
+
+// Assets to serve the swagger ui scripts and styles from another base url,
+// such as a self-hosted copy of swagger-ui-dist
+func Assets(s string) Option {
+	return func(o *Options) {
+		o.Assets = strings.TrimSuffix(s, "/")
+	}
+}
@@ -0,0 +1,325 @@
+package swagger
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vaniila/hyper/router"
+)
+
+const version = "3.0.0"
+
+type generator struct {
+	doc  *Document
+	tags map[string]bool
+}
+
+// Generate walks the router tree and builds an OpenAPI 3 document
+func Generate(r router.Service, opts ...Option) *Document {
+	o := newOptions(opts...)
+	doc := &Document{
+		OpenAPI: version,
+		Info: &Info{
+			Title:       o.Title,
+			Description: o.Description,
+			Version:     o.Version,
+		},
+		Paths: make(map[string]*PathItem),
+	}
+	for _, s := range o.Servers {
+		doc.Servers = append(doc.Servers, &Server{URL: s})
+	}
+	g := &generator{
+		doc:  doc,
+		tags: make(map[string]bool),
+	}
+	g.walk([]string{""}, nil, r.Routes())
+	return doc
+}
+
+func (v *generator) walk(prefixes []string, tags []string, routes []router.Route) {
+	for _, route := range routes {
+		conf := route.Config()
+		switch {
+		case conf.Namespace():
+			var paths []string
+			for _, prefix := range prefixes {
+				paths = append(paths, join(prefix, conf.Pattern()))
+				for _, alias := range conf.Aliases() {
+					paths = append(paths, join(prefix, alias))
+				}
+			}
+			stags := tags
+			if name := conf.Name(); name != "" {
+				stags = append(append([]string{}, tags...), name)
+				if !v.tags[name] {
+					v.tags[name] = true
+					v.doc.Tags = append(v.doc.Tags, &Tag{Name: name, Description: conf.Summary()})
+				}
+			}
+			v.walk(paths, stags, conf.Routes())
+		case conf.HTTP():
+			for i, prefix := range prefixes {
+				v.add(join(prefix, conf.Pattern()), tags, conf, i == 0)
+				for _, alias := range conf.Aliases() {
+					v.add(join(prefix, alias), tags, conf, false)
+				}
+			}
+		}
+	}
+}
+
+func (v *generator) add(pat string, tags []string, conf router.RouteConfig, primary bool) {
+	path, pathParams := normalize(pat)
+	item, ok := v.doc.Paths[path]
+	if !ok {
+		item = new(PathItem)
+		v.doc.Paths[path] = item
+	}
+	op := &Operation{
+		Tags:        tags,
+		Summary:     conf.Summary(),
+		Description: conf.Doc(),
+		Responses:   make(map[string]*Response),
+	}
+	if primary {
+		op.OperationID = conf.Name()
+	}
+	declared := make(map[string]bool)
+	v.params(op, conf.Params(), declared)
+	for _, pp := range pathParams {
+		if !declared[pp.name] {
+			op.Parameters = append(op.Parameters, &Parameter{
+				Name:     pp.name,
+				In:       "path",
+				Required: true,
+				Schema:   &router.Schema{Type: "string"},
+			})
+		}
+		for _, param := range op.Parameters {
+			if param.In == "path" && param.Name == pp.name && param.Schema.Pattern == "" {
+				param.Schema.Pattern = pp.pattern
+			}
+		}
+	}
+	for _, model := range conf.Models() {
+		code, res := "default", &Response{Description: "Default response"}
+		if c := model.Code(); c != 0 {
+			code, res.Description = strconv.Itoa(c), http.StatusText(c)
+		}
+		if schema := model.Schema(); schema != nil {
+			res.Content = map[string]*MediaType{
+				"application/json": {Schema: schema},
+			}
+		}
+		op.Responses[code] = res
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["default"] = &Response{Description: "Default response"}
+	}
+	item.set(conf.Method(), op)
+}
+
+func (v *generator) params(op *Operation, params []router.Param, declared map[string]bool) {
+	for _, param := range params {
+		conf := param.Config()
+		switch conf.Type() {
+		case router.ParamOneOf:
+			var group []string
+			for _, p := range conf.OneOf() {
+				group = append(group, reference(p))
+			}
+			op.OneOf = append(op.OneOf, group)
+			v.params(op, conf.OneOf(), declared)
+		case router.ParamBody:
+			v.body(op, conf)
+		default:
+			var deps []string
+			for _, dep := range conf.DependsOn() {
+				deps = append(deps, reference(dep))
+			}
+			p := &Parameter{
+				Name:        conf.Name(),
+				In:          location(conf.Type()),
+				Description: description(conf),
+				Required:    conf.Require() || conf.Type() == router.ParamParam,
+				Schema:      schema(conf),
+				DependsOn:   deps,
+			}
+			if p.In == "path" {
+				declared[p.Name] = true
+			}
+			op.Parameters = append(op.Parameters, p)
+		}
+	}
+}
+
+const (
+	mediaForm      = "application/x-www-form-urlencoded"
+	mediaMultipart = "multipart/form-data"
+	mediaJSON      = "application/json"
+)
+
+func object() *router.Schema {
+	return &router.Schema{Type: "object", Properties: make(map[string]*router.Schema)}
+}
+
+func (v *generator) body(op *Operation, conf router.ParamConfig) {
+	if op.RequestBody == nil {
+		op.RequestBody = &RequestBody{
+			Content: map[string]*MediaType{
+				mediaForm: {Schema: object()},
+				mediaJSON: {Schema: object()},
+			},
+		}
+	}
+	s := schema(conf)
+	s.Description = description(conf)
+	for _, dep := range conf.DependsOn() {
+		s.DependsOn = append(s.DependsOn, reference(dep))
+	}
+	// files are only accepted through multipart forms
+	if conf.Format() == router.File {
+		if form, ok := op.RequestBody.Content[mediaForm]; ok {
+			delete(op.RequestBody.Content, mediaForm)
+			delete(op.RequestBody.Content, mediaJSON)
+			op.RequestBody.Content[mediaMultipart] = form
+		}
+	}
+	if conf.Require() {
+		op.RequestBody.Required = true
+	}
+	for typ, media := range op.RequestBody.Content {
+		parent, name := media.Schema, conf.Name()
+		// json bodies address nested fields with dotted names
+		if typ == mediaJSON {
+			keys := strings.Split(name, ".")
+			for _, key := range keys[:len(keys)-1] {
+				child, ok := parent.Properties[key]
+				if !ok || child.Properties == nil {
+					child = object()
+					parent.Properties[key] = child
+				}
+				parent = child
+			}
+			name = keys[len(keys)-1]
+		}
+		parent.Properties[name] = s
+		if conf.Require() {
+			parent.Required = append(parent.Required, name)
+		}
+	}
+}
+
+func schema(conf router.ParamConfig) *router.Schema {
+	s := router.FormatSchema(conf.Format())
+	if b := conf.Default(); len(b) > 0 {
+		s.Default = value(conf.Format(), b)
+	}
+	if min := conf.Min(); min != nil {
+		s.Minimum = min
+	}
+	if max := conf.Max(); max != nil {
+		s.Maximum = max
+	}
+	s.MinLength = conf.MinLength()
+	s.MaxLength = conf.MaxLength()
+	if pattern := conf.Pattern(); pattern != nil {
+		s.Pattern = pattern.String()
+	}
+	for _, e := range conf.Enum() {
+		s.Enum = append(s.Enum, value(conf.Format(), []byte(e)))
+	}
+	if conf.Multiple() {
+		return &router.Schema{Type: "array", Items: s}
+	}
+	return s
+}
+
+// value converts raw input into its typed representation for documents
+func value(format int, b []byte) interface{} {
+	switch parsed, ok := router.Val(format, b); v := parsed.(type) {
+	case []byte:
+		return string(v)
+	default:
+		if ok {
+			return v
+		}
+	}
+	return string(b)
+}
+
+func description(conf router.ParamConfig) string {
+	if doc := conf.Doc(); doc != "" {
+		return doc
+	}
+	return conf.Summary()
+}
+
+func location(typ router.ParamType) string {
+	switch typ {
+	case router.ParamParam:
+		return "path"
+	}
+	return typ.String()
+}
+
+func reference(p router.Param) string {
+	conf := p.Config()
+	return fmt.Sprintf("%v.%v", location(conf.Type()), conf.Name())
+}
+
+// pathParam is a parameter parsed from a route pattern
+type pathParam struct {
+	name, pattern string
+}
+
+// normalize converts chi route patterns into OpenAPI path templates, braces
+// are counted as chi does so regexps may hold quantifiers like {3}
+func normalize(pat string) (string, []pathParam) {
+	var (
+		params []pathParam
+		path   strings.Builder
+	)
+	for i := 0; i < len(pat); i++ {
+		if pat[i] != '{' {
+			path.WriteByte(pat[i])
+			continue
+		}
+		depth, end := 1, -1
+		for j := i + 1; j < len(pat) && end < 0; j++ {
+			switch pat[j] {
+			case '{':
+				depth++
+			case '}':
+				if depth--; depth == 0 {
+					end = j
+				}
+			}
+		}
+		if end < 0 {
+			path.WriteString(pat[i:])
+			break
+		}
+		p := pathParam{name: pat[i+1 : end]}
+		if k := strings.IndexByte(p.name, ':'); k >= 0 {
+			p.name, p.pattern = p.name[:k], p.name[k+1:]
+		}
+		params = append(params, p)
+		path.WriteString("{" + p.name + "}")
+		i = end
+	}
+	if path.Len() == 0 {
+		return "/", params
+	}
+	return path.String(), params
+}
+
+func join(prefix, pat string) string {
+	if prefix == "" {
+		return pat
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(pat, "/")
+}
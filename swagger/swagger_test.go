@@ -0,0 +1,111 @@
+package swagger_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vaniila/hyper"
+	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/swagger"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden documents")
+
+func handle(c router.Context) {}
+
+// golden compares the document generated for the router with the one kept
+// in testdata
+func golden(t *testing.T, name string, r router.Service) {
+	t.Helper()
+	b := swagger.Generate(r, swagger.Title("Test"), swagger.Version("1.0.0")).JSON()
+	file := filepath.Join("testdata", name+".json")
+	if *update {
+		if err := ioutil.WriteFile(file, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(b), bytes.TrimSpace(want)) {
+		t.Errorf("%s document differs from %s:\n%s", name, file, b)
+	}
+}
+
+func TestGenerateNestedNamespace(t *testing.T) {
+	r := router.New()
+	api := r.Namespace("/api").Name("api").Summary(`public api`)
+	users := api.Namespace("/users").Name("users").Summary(`user accounts`)
+	users.
+		Get("/{id:[0-9]{3}}").
+		Name("GetUser").
+		Summary(`gets a user`).
+		Params(
+			hyper.Param("id").
+				Format(hyper.Int).
+				Doc(`user id`),
+		).
+		Handle(handle)
+	users.
+		Get("/{id}/posts/{slug:[a-z-]+}").
+		Name("ListUserPosts").
+		Handle(handle)
+	golden(t, "namespace", r)
+}
+
+func TestGenerateAlias(t *testing.T) {
+	r := router.New()
+	r.
+		Namespace("/v1").
+		Alias("/legacy").
+		Get("/items").
+		Alias("/things").
+		Name("ListItems").
+		Summary(`lists items`).
+		Handle(handle)
+	golden(t, "alias", r)
+}
+
+func TestGenerateGroups(t *testing.T) {
+	r := router.New()
+	name := hyper.Query("name").Format(hyper.Text)
+	r.
+		Post("/search").
+		Name("Search").
+		Params(
+			hyper.OneOf(
+				hyper.Query("id").Format(hyper.Int),
+				name,
+			),
+			hyper.Query("exact").
+				Format(hyper.Bool).
+				DependsOn(name),
+			hyper.Body("filter.tags").
+				Format(hyper.Text).
+				Multiple(true).
+				DependsOn(name),
+		).
+		Handle(handle)
+	golden(t, "groups", r)
+}
+
+func TestUIAssets(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []swagger.Option
+		want string
+	}{
+		{"default", nil, `href="https://unpkg.com/swagger-ui-dist@3/swagger-ui.css"`},
+		{"self-hosted", []swagger.Option{swagger.Assets("/static/swagger/")}, `src="/static/swagger/swagger-ui-bundle.js"`},
+	}
+	for _, tt := range tests {
+		if b := swagger.UI("Test", "/swagger/openapi.json", tt.opts...); !strings.Contains(string(b), tt.want) {
+			t.Errorf("%s: page does not contain %s:\n%s", tt.name, tt.want, b)
+		}
+	}
+}
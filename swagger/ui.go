@@ -0,0 +1,38 @@
+package swagger
+
+import (
+	"bytes"
+	"html/template"
+)
+
+var tmplUI = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="{{.Assets}}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="{{.Assets}}/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = function() {
+      window.ui = SwaggerUIBundle({
+        url: "{{.URL}}",
+        dom_id: "#swagger-ui",
+        deepLinking: true
+      });
+    };
+  </script>
+</body>
+</html>
+`))
+
+// UI renders the swagger ui page for the document url, the assets are loaded
+// from the base url of the options
+func UI(title, url string, opts ...Option) []byte {
+	o := newOptions(opts...)
+	b := new(bytes.Buffer)
+	tmplUI.Execute(b, struct{ Title, URL, Assets string }{title, url, o.Assets})
+	return b.Bytes()
+}
@@ -0,0 +1,103 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// node keeps json object keys in their original order
+type node struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func decode(dec *json.Decoder) interface{} {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n := &node{values: make(map[string]interface{})}
+			for dec.More() {
+				k, _ := dec.Token()
+				key, _ := k.(string)
+				n.keys = append(n.keys, key)
+				n.values[key] = decode(dec)
+			}
+			dec.Token()
+			return n
+		case '[':
+			var a []interface{}
+			for dec.More() {
+				a = append(a, decode(dec))
+			}
+			dec.Token()
+			return a
+		}
+	}
+	return tok
+}
+
+func toYAML(b []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	buf := new(bytes.Buffer)
+	writeYAML(buf, decode(dec), 0)
+	return buf.Bytes()
+}
+
+func writeYAML(buf *bytes.Buffer, o interface{}, depth int) {
+	indent := strings.Repeat("  ", depth)
+	switch v := o.(type) {
+	case *node:
+		for _, key := range v.keys {
+			buf.WriteString(indent + scalar(key) + ":")
+			writeValue(buf, v.values[key], depth+1)
+		}
+	case []interface{}:
+		for _, item := range v {
+			buf.WriteString(indent + "-")
+			writeValue(buf, item, depth+1)
+		}
+	}
+}
+
+func writeValue(buf *bytes.Buffer, o interface{}, depth int) {
+	switch v := o.(type) {
+	case *node:
+		if len(v.keys) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteString("\n")
+		writeYAML(buf, v, depth)
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteString("\n")
+		writeYAML(buf, v, depth)
+	default:
+		buf.WriteString(" " + scalar(v) + "\n")
+	}
+}
+
+func scalar(o interface{}) string {
+	switch v := o.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return strconv.Quote(v)
+	}
+	return ""
+}
@@ -12,4 +12,8 @@ const (
 	ActionMessage
 	ActionMessageSuccessful
 	ActionMessageFailure
+	ActionPresenceJoin
+	ActionPresenceLeave
+	ActionReply
+	ActionReplyFailure
 )
@@ -1,10 +1,13 @@
 package sync
 
+import "sync"
+
 type channel struct {
 	namespace    Namespace
 	name         string
 	nsubscribers []Context
 	server       *server
+	sync.RWMutex
 }
 
 func (v *channel) Namespace() Namespace {
@@ -15,36 +18,64 @@ func (v *channel) Name() string {
 	return v.name
 }
 
+// NodeSubscribers returns a copy of the local subscribers, the presence
+// worker reads them while connections subscribe
 func (v *channel) NodeSubscribers() []Context {
-	return v.nsubscribers
+	v.RLock()
+	defer v.RUnlock()
+	return append([]Context(nil), v.nsubscribers...)
 }
 
-func (v *channel) Has(c Context) bool {
-	for _, s := range v.nsubscribers {
-		if s.MachineID() == c.MachineID() && s.ProcessID() == c.ProcessID() {
-			return true
-		}
+// Members lists the identities subscribed to the channel across the cluster
+func (v *channel) Members() []Member {
+	if v.server == nil || v.server.presence == nil {
+		return localMembers(v)
 	}
-	return false
+	return v.server.presence.members(keyOf(v))
+}
+
+func (v *channel) Has(c Context) bool {
+	v.RLock()
+	defer v.RUnlock()
+	return v.index(c) >= 0
 }
 
 func (v *channel) Subscribe(c Context) Channel {
-	subscribed := v.Has(c)
+	v.Lock()
+	subscribed := v.index(c) >= 0
 	if !subscribed {
 		v.nsubscribers = append(v.nsubscribers, c)
+	}
+	v.Unlock()
+	if !subscribed {
 		c.Subscriptions().Add(v)
+		v.changed()
 	}
 	return v
 }
 
 func (v *channel) Unsubscribe(c Context) Channel {
+	v.Lock()
+	i := v.index(c)
+	if i >= 0 {
+		v.nsubscribers = append(v.nsubscribers[:i], v.nsubscribers[i+1:]...)
+	}
+	v.Unlock()
+	if i >= 0 {
+		c.Subscriptions().Del(v)
+		v.changed()
+	}
+	return v
+}
+
+// index finds the subscriber of the connection, the caller holds the lock
+func (v *channel) index(c Context) int {
 	for i, s := range v.nsubscribers {
 		if s.MachineID() == c.MachineID() && s.ProcessID() == c.ProcessID() {
-			v.nsubscribers = append(v.nsubscribers[:i], v.nsubscribers[i+1:]...)
-			c.Subscriptions().Del(v)
+			return i
 		}
 	}
-	return v
+	return -1
 }
 
 func (v *channel) Write(p *Packet, s ...*Condition) error {
@@ -62,16 +93,37 @@ func (v *channel) Write(p *Packet, s ...*Condition) error {
 	if p.Channel == "" {
 		p.Channel = v.name
 	}
+	if patternOf(v.namespace, p.Channel) {
+		return PatternNotWritable.Fill(p.Namespace, p.Channel)
+	}
 	if p.Action == ActionUnknown {
 		p.Action = ActionMessage
 	}
+	// every node tracks signed packets by the same id
+	if p.Signature && p.ID == "" {
+		p.ID = newID()
+	}
 	d := &Distribution{
 		Packet:    p,
 		Condition: c,
 	}
+	if err := v.server.history.record(v, d); err != nil {
+		return err
+	}
 	return v.server.Publish(d)
 }
 
+// changed updates the cluster presence, patterns are not channels and
+// have no members of their own
+func (v *channel) changed() {
+	if isPatternChannel(v) {
+		return
+	}
+	if v.server != nil && v.server.presence != nil {
+		v.server.presence.update(v)
+	}
+}
+
 func (v *channel) BeforeOpen() {
 
 }
@@ -5,6 +5,7 @@ import "sync"
 type channels struct {
 	namespace Namespace
 	channels  map[string]Channel
+	patterns  pattern
 	server    *server
 	sync.RWMutex
 }
@@ -43,6 +44,9 @@ func (v *channels) Add(name string) Channels {
 		}
 		v.Lock()
 		v.channels[name] = c
+		if patternOf(v.namespace, name) {
+			v.patterns.insert(segments(name), c)
+		}
 		v.Unlock()
 		return v
 	}
@@ -51,20 +55,51 @@ func (v *channels) Add(name string) Channels {
 
 func (v *channels) Del(name string) Channels {
 	v.RLock()
-	_, ok := v.channels[name]
+	c, ok := v.channels[name]
 	v.RUnlock()
 	if ok {
 		v.Lock()
 		delete(v.channels, name)
+		if patternOf(v.namespace, name) {
+			v.patterns.remove(segments(name), c)
+		}
 		v.Unlock()
 	}
 	return v
 }
 
+// Match returns the channel of the name and the pattern channels matching it
+func (v *channels) Match(name string) []Channel {
+	var list []Channel
+	v.RLock()
+	defer v.RUnlock()
+	if c, ok := v.channels[name]; ok {
+		list = append(list, c)
+	}
+	var patterns []Channel
+	v.patterns.match(segments(name), &patterns)
+	for _, c := range patterns {
+		if c.Name() != name {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// List returns a copy of the channels, the presence worker lists them while
+// connections add channels
 func (v *channels) List() map[string]Channel {
-	return v.channels
+	v.RLock()
+	defer v.RUnlock()
+	list := make(map[string]Channel, len(v.channels))
+	for name, c := range v.channels {
+		list[name] = c
+	}
+	return list
 }
 
 func (v *channels) Len() int {
+	v.RLock()
+	defer v.RUnlock()
 	return len(v.channels)
 }
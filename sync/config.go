@@ -1,5 +1,7 @@
 package sync
 
+import "time"
+
 type config struct {
 	namespace     string
 	aliases       []string
@@ -10,6 +12,9 @@ type config struct {
 	catch         HandlerFunc
 	handlers      []Handler
 	middleware    HandlerFuncs
+	historySize   int
+	historyTTL    time.Duration
+	patterns      bool
 	channels      Channels
 }
 
@@ -49,6 +54,18 @@ func (v *config) Doc() string {
 	return v.documentation
 }
 
+func (v *config) HistorySize() int {
+	return v.historySize
+}
+
+func (v *config) HistoryTTL() time.Duration {
+	return v.historyTTL
+}
+
+func (v *config) Patterns() bool {
+	return v.patterns
+}
+
 func (v *config) Channels() Channels {
 	return v.channels
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/websocket"
 	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/websocket/outbound"
 )
 
 type connection struct {
@@ -23,7 +24,9 @@ type connection struct {
 	message              MessageAdaptor
 	logger               LoggerAdaptor
 	server               Service
+	delivery             *delivery
 	conn                 *websocket.Conn
+	queue                *outbound.Queue
 }
 
 func (v *connection) MachineID() string {
@@ -78,12 +81,21 @@ func (v *connection) Logger() LoggerAdaptor {
 	return v.logger
 }
 
+// Write sends a packet, signed packets are tracked until the client
+// acknowledges them
 func (v *connection) Write(p *Packet) error {
+	if p.GetSignature() && v.delivery != nil {
+		return v.delivery.track(v, p, v.send)
+	}
+	return v.send(p)
+}
+
+func (v *connection) send(p *Packet) error {
 	b, err := proto.Marshal(p)
 	if err != nil {
 		return err
 	}
-	return v.conn.WriteMessage(websocket.BinaryMessage, b)
+	return v.queue.Write(websocket.BinaryMessage, b)
 }
 
 func (v *connection) Close() error {
@@ -96,7 +108,8 @@ func (v *connection) BeforeOpen() {
 func (v *connection) AfterClose() {
 	// clean up channels
 	// close channels that has no subscribers in it
-	for _, c := range v.Subscriptions().List() {
+	subs := v.Subscriptions()
+	for _, c := range append(append([]Channel{}, subs.List()...), subs.Patterns()...) {
 		c.Unsubscribe(v)
 		l := len(c.NodeSubscribers())
 		if l == 0 {
@@ -0,0 +1,121 @@
+package sync
+
+import (
+	"sync"
+	"time"
+)
+
+// unacked is a signed packet waiting for the client acknowledgement
+type unacked struct {
+	packet   *Packet
+	send     func(*Packet) error
+	attempts int
+	timer    *time.Timer
+}
+
+// delivery tracks signed packets written to connections, packets which are
+// not acknowledged in time are sent again and reported once retries run out
+type delivery struct {
+	server  *server
+	timeout time.Duration
+	retries int
+	pending map[Context]map[string]*unacked
+	sync.Mutex
+}
+
+func newDelivery(s *server, timeout time.Duration, retries int) *delivery {
+	return &delivery{
+		server:  s,
+		timeout: timeout,
+		retries: retries,
+		pending: make(map[Context]map[string]*unacked),
+	}
+}
+
+// track registers a signed packet of the connection and sends it
+func (v *delivery) track(c Context, p *Packet, send func(*Packet) error) error {
+	if v.timeout <= 0 {
+		return send(p)
+	}
+	if p.ID == "" {
+		p.ID = newID()
+	}
+	id := p.ID
+	v.Lock()
+	group, ok := v.pending[c]
+	if !ok {
+		group = make(map[string]*unacked)
+		v.pending[c] = group
+	}
+	if u, ok := group[id]; ok {
+		u.timer.Stop()
+	}
+	group[id] = &unacked{
+		packet: p,
+		send:   send,
+		timer:  time.AfterFunc(v.timeout, func() { v.expire(c, id) }),
+	}
+	v.Unlock()
+	return send(p)
+}
+
+// ack settles a packet the client confirmed
+func (v *delivery) ack(c Context, id string) bool {
+	v.Lock()
+	defer v.Unlock()
+	u, ok := v.pending[c][id]
+	if !ok {
+		return false
+	}
+	u.timer.Stop()
+	v.remove(c, id)
+	return true
+}
+
+// expire sends an unacknowledged packet again or reports it
+func (v *delivery) expire(c Context, id string) {
+	v.Lock()
+	u, ok := v.pending[c][id]
+	if !ok {
+		v.Unlock()
+		return
+	}
+	if u.attempts < v.retries {
+		u.attempts++
+		u.timer.Reset(v.timeout)
+		v.Unlock()
+		u.send(u.packet)
+		return
+	}
+	v.remove(c, id)
+	v.Unlock()
+	v.fail(c, u.packet)
+}
+
+// drop reports the packets still pending when the connection closes
+func (v *delivery) drop(c Context) {
+	v.Lock()
+	group := v.pending[c]
+	delete(v.pending, c)
+	v.Unlock()
+	for _, u := range group {
+		u.timer.Stop()
+		v.fail(c, u.packet)
+	}
+}
+
+func (v *delivery) remove(c Context, id string) {
+	delete(v.pending[c], id)
+	if len(v.pending[c]) == 0 {
+		delete(v.pending, c)
+	}
+}
+
+func (v *delivery) fail(c Context, p *Packet) {
+	if v.server.logger != nil {
+		v.server.logger.Warn(PacketNotDelivered.Fill(p.GetID()).Error())
+	}
+	if fn := v.server.hookuf; fn != nil {
+		fn(p, c)
+	}
+}
@@ -0,0 +1,125 @@
+package sync
+
+import (
+	stdsync "sync"
+	"testing"
+	"time"
+)
+
+// connContext is embedded under another name since Context has a method of
+// the same name
+type connContext = Context
+
+type deliveryConn struct {
+	connContext
+}
+
+// deliveryLog counts the sends and failures of a delivery
+type deliveryLog struct {
+	sends, failures int
+	stdsync.Mutex
+}
+
+func (v *deliveryLog) send(*Packet) error {
+	v.Lock()
+	v.sends++
+	v.Unlock()
+	return nil
+}
+
+func (v *deliveryLog) counts() (int, int) {
+	v.Lock()
+	defer v.Unlock()
+	return v.sends, v.failures
+}
+
+func newDeliveryLog(timeout time.Duration, retries int) (*delivery, *deliveryLog) {
+	log := new(deliveryLog)
+	s := &server{hookuf: func(*Packet, Context) {
+		log.Lock()
+		log.failures++
+		log.Unlock()
+	}}
+	return newDelivery(s, timeout, retries), log
+}
+
+func TestDeliveryRetries(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		retries  int
+		ack      time.Duration
+		sends    int
+		failures int
+	}{
+		{"untracked", 0, 3, -1, 1, 0},
+		{"no retries", timeout, 0, -1, 1, 1},
+		{"retries run out", timeout, 2, -1, 3, 1},
+		{"acknowledged", timeout, 2, 0, 1, 0},
+		{"acknowledged after a resend", timeout, 2, timeout * 3 / 2, 2, 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d, log := newDeliveryLog(tt.timeout, tt.retries)
+			c := &deliveryConn{}
+			p := &Packet{Signature: true}
+			if err := d.track(c, p, log.send); err != nil {
+				t.Fatal(err)
+			}
+			if tt.ack >= 0 {
+				time.Sleep(tt.ack)
+				if !d.ack(c, p.GetID()) {
+					t.Error("pending packet was not acknowledged")
+				}
+			}
+			time.Sleep(time.Duration(tt.retries+1)*timeout + 2*timeout)
+			if sends, failures := log.counts(); sends != tt.sends || failures != tt.failures {
+				t.Errorf("%d sends and %d failures, want %d and %d", sends, failures, tt.sends, tt.failures)
+			}
+			if d.ack(c, p.GetID()) {
+				t.Error("settled packet was acknowledged again")
+			}
+			if len(d.pending) != 0 {
+				t.Errorf("%d connections still pending", len(d.pending))
+			}
+		})
+	}
+}
+
+func TestDeliveryAssignsID(t *testing.T) {
+	d, log := newDeliveryLog(time.Minute, 0)
+	c := &deliveryConn{}
+	p := &Packet{Signature: true}
+	d.track(c, p, log.send)
+	if p.GetID() == "" {
+		t.Fatal("signed packet has no id")
+	}
+	if d.ack(c, "other") {
+		t.Error("unknown id was acknowledged")
+	}
+	if d.ack(&deliveryConn{}, p.GetID()) {
+		t.Error("packet was acknowledged by another connection")
+	}
+	if !d.ack(c, p.GetID()) {
+		t.Error("packet was not acknowledged")
+	}
+}
+
+func TestDeliveryDrop(t *testing.T) {
+	const timeout = 30 * time.Millisecond
+	d, log := newDeliveryLog(timeout, 3)
+	c := &deliveryConn{}
+	d.track(c, &Packet{Signature: true}, log.send)
+	d.track(c, &Packet{Signature: true}, log.send)
+	d.drop(c)
+	time.Sleep(3 * timeout)
+	if sends, failures := log.counts(); sends != 2 || failures != 2 {
+		t.Errorf("%d sends and %d failures, want 2 and 2", sends, failures)
+	}
+	if len(d.pending) != 0 {
+		t.Errorf("%d connections still pending", len(d.pending))
+	}
+}
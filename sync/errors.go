@@ -13,4 +13,9 @@ var (
 	ChannelNotExist          = fault.Format("channel %s does not exist")
 	ChannelAlreadySubscribed = fault.Format("`%s:%s` has already been subscribed")
 	ChannelNotSubscribed     = fault.Format("`%s:%s` has not been subscribed")
+	InvalidPattern           = fault.Format("channel pattern %s is Invalid")
+	PatternNotWritable       = fault.Format("`%s:%s` is a pattern and cannot be written to")
+
+	MessageNotHandled  = fault.Format("message [%s] could not be handled")
+	PacketNotDelivered = fault.Format("packet [%s] has not been acknowledged")
 )
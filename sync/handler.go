@@ -1,8 +1,9 @@
 package sync
 
 type handler struct {
-	name string
-	fn   HandlerFunc
+	name  string
+	fn    HandlerFunc
+	reply ReplyFunc
 }
 
 func (v *handler) Name() string {
@@ -12,3 +13,7 @@ func (v *handler) Name() string {
 func (v *handler) Func() HandlerFunc {
 	return v.fn
 }
+
+func (v *handler) Reply() ReplyFunc {
+	return v.reply
+}
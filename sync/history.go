@@ -0,0 +1,204 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/vaniila/hyper/cache"
+)
+
+// historyLimit bounds the entries of channels whose history is only limited
+// by age
+const historyLimit = 1000
+
+// historyEntry is a message kept in the channel history, data holds the
+// distribution so replays honour its condition
+type historyEntry struct {
+	Sequence uint64 `json:"seq"`
+	ID       string `json:"id"`
+	Time     int64  `json:"time"`
+	Data     []byte `json:"data"`
+}
+
+// history keeps the recent messages of channels whose namespace enables
+// it. Each channel is a ring of cache entries numbered by a counter, caches
+// implementing cache.Counter number messages uniquely across the cluster,
+// other caches only within the node
+type history struct {
+	server *server
+	locks  map[channelKey]*sync.Mutex
+	sync.Mutex
+}
+
+func newHistory(s *server) *history {
+	return &history{
+		server: s,
+		locks:  make(map[channelKey]*sync.Mutex),
+	}
+}
+
+// enabled tells whether the channel keeps history, patterns never do since
+// their messages are kept by the matching channels
+func enabled(ch Channel) bool {
+	c := ch.Namespace().Config()
+	return !isPatternChannel(ch) && (c.HistorySize() > 0 || c.HistoryTTL() > 0)
+}
+
+// capacity returns the number of entries kept for channels of the namespace
+func capacity(n Namespace) uint64 {
+	if size := n.Config().HistorySize(); size > 0 {
+		return uint64(size)
+	}
+	return historyLimit
+}
+
+// record numbers the packet and stores its distribution in the channel
+// history, it is called before the distribution is published
+func (v *history) record(ch Channel, d *Distribution) error {
+	if v.server.cache == nil || !enabled(ch) {
+		return nil
+	}
+	if d.Packet.ID == "" {
+		d.Packet.ID = newID()
+	}
+	n, key := ch.Namespace(), keyOf(ch)
+	mu := v.lock(key)
+	mu.Lock()
+	defer mu.Unlock()
+	seq, err := v.next(key, 1)
+	if err != nil {
+		return err
+	}
+	d.Packet.Sequence = seq
+	b, err := proto.Marshal(d)
+	if err != nil {
+		return err
+	}
+	e, err := json.Marshal(&historyEntry{
+		Sequence: seq,
+		ID:       d.Packet.ID,
+		Time:     time.Now().UnixNano(),
+		Data:     b,
+	})
+	if err != nil {
+		return err
+	}
+	// the entry replaces the one a full ring of messages ago
+	return v.server.cache.Set(historyKey(key, seq%capacity(n)), e, n.Config().HistoryTTL())
+}
+
+// sequence returns the last sequence number of the channel
+func (v *history) sequence(ch Channel) uint64 {
+	if v.server.cache == nil || !enabled(ch) {
+		return 0
+	}
+	key := keyOf(ch)
+	mu := v.lock(key)
+	mu.Lock()
+	defer mu.Unlock()
+	seq, _ := v.next(key, 0)
+	return seq
+}
+
+// replay writes the messages after the requested sequence or id to the
+// connection, everything kept is replayed when the position is unknown
+func (v *history) replay(ch Channel, c Context, seq uint64, since string) {
+	if v.server.cache == nil || !enabled(ch) {
+		return
+	}
+	n, last := ch.Namespace(), v.sequence(ch)
+	first := uint64(1)
+	if size := capacity(n); last > size {
+		first = last - size + 1
+	}
+	if since == "" && seq <= last && seq+1 > first {
+		first = seq + 1
+	}
+	entries := v.load(keyOf(ch), n, first, last)
+	start := 0
+	if since != "" {
+		for i, e := range entries {
+			if e.ID == since {
+				start = i + 1
+				break
+			}
+		}
+	}
+	for _, e := range entries[start:] {
+		d := &Distribution{}
+		if err := proto.Unmarshal(e.Data, d); err != nil || d.Packet == nil {
+			continue
+		}
+		if matchCondition(d.Condition, c) {
+			c.Write(d.Packet)
+		}
+	}
+}
+
+// load reads the entries numbered first to last which are still kept and
+// younger than the namespace ttl
+func (v *history) load(key channelKey, n Namespace, first, last uint64) []*historyEntry {
+	var (
+		entries []*historyEntry
+		size    = capacity(n)
+		after   int64
+	)
+	if ttl := n.Config().HistoryTTL(); ttl > 0 {
+		after = time.Now().Add(-ttl).UnixNano()
+	}
+	for seq := first; seq <= last && seq > 0; seq++ {
+		b, err := v.server.cache.Get(historyKey(key, seq%size))
+		if err != nil || len(b) == 0 {
+			continue
+		}
+		e := new(historyEntry)
+		if json.Unmarshal(b, e) != nil || e.Sequence != seq || e.Time < after {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// next adds n to the sequence of the channel and returns it, the caller
+// holds the channel lock which guards caches without counters
+func (v *history) next(key channelKey, n uint64) (uint64, error) {
+	k := sequenceKey(key)
+	if c, ok := v.server.cache.(cache.Counter); ok {
+		return c.Incr(k, n)
+	}
+	var seq uint64
+	if b, err := v.server.cache.Get(k); err == nil && len(b) > 0 {
+		seq, _ = strconv.ParseUint(string(b), 10, 64)
+	}
+	if n == 0 {
+		return seq, nil
+	}
+	seq += n
+	// the sequence does not expire so numbers keep increasing
+	return seq, v.server.cache.Set(k, []byte(strconv.FormatUint(seq, 10)), 0)
+}
+
+// lock returns the mutex of a channel, channels are recorded independently
+func (v *history) lock(key channelKey) *sync.Mutex {
+	v.Lock()
+	defer v.Unlock()
+	mu, ok := v.locks[key]
+	if !ok {
+		mu = new(sync.Mutex)
+		v.locks[key] = mu
+	}
+	return mu
+}
+
+func historyKey(key channelKey, slot uint64) []byte {
+	return []byte(fmt.Sprintf("sync:history:%s:%s:%d", key.namespace, key.channel, slot))
+}
+
+func sequenceKey(key channelKey) []byte {
+	return []byte(fmt.Sprintf("sync:history:%s:%s:seq", key.namespace, key.channel))
+}
@@ -0,0 +1,180 @@
+package sync
+
+import (
+	"fmt"
+	stdsync "sync"
+	"testing"
+	"time"
+)
+
+// counterCache is a fake cache with atomic counters shared by nodes
+type counterCache struct {
+	*fakeCache
+	counters map[string]uint64
+}
+
+func newCounterCache() *counterCache {
+	return &counterCache{newFakeCache(), make(map[string]uint64)}
+}
+
+func (v *counterCache) Incr(key []byte, n uint64) (uint64, error) {
+	v.Lock()
+	defer v.Unlock()
+	v.counters[string(key)] += n
+	return v.counters[string(key)], nil
+}
+
+func newHistoryChannel(c *fakeCache, size int, ttl time.Duration) Channel {
+	var s *server
+	if c != nil {
+		s = New(Cache(c)).(*server)
+	} else {
+		s = New().(*server)
+	}
+	return s.Namespace("chat").History(size, ttl).Channels().Add("room").Get("room")
+}
+
+// write records n messages numbered from one
+func write(t *testing.T, ch Channel, n int) []*Packet {
+	var list []*Packet
+	for i := 1; i <= n; i++ {
+		p := &Packet{Action: ActionMessage, Namespace: "chat", Channel: "room", Message: []byte(fmt.Sprint(i))}
+		if err := ch.(*channel).server.history.record(ch, &Distribution{Packet: p}); err != nil {
+			t.Error(err)
+		}
+		list = append(list, p)
+	}
+	return list
+}
+
+func replayed(ch Channel, seq uint64, since string) string {
+	c := newTestConn("u1", 1)
+	ch.(*channel).server.history.replay(ch, c, seq, since)
+	var s string
+	for _, p := range c.packets {
+		s += string(p.Message)
+	}
+	return s
+}
+
+func TestHistoryReplay(t *testing.T) {
+	ch := newHistoryChannel(newFakeCache(), 5, 0)
+	packets := write(t, ch, 8)
+	for i, p := range packets {
+		if p.Sequence != uint64(i+1) || p.ID == "" {
+			t.Errorf("packet %d has sequence %d and id %q", i+1, p.Sequence, p.ID)
+		}
+	}
+	if got := ch.(*channel).server.history.sequence(ch); got != 8 {
+		t.Errorf("sequence = %d, want 8", got)
+	}
+	tests := []struct {
+		name  string
+		seq   uint64
+		since string
+		want  string
+	}{
+		{"after sequence", 6, "", "78"},
+		{"up to date", 8, "", ""},
+		{"sequence dropped from the ring", 1, "", "45678"},
+		{"unknown sequence", 20, "", "45678"},
+		{"after id", 0, packets[4].ID, "678"},
+		{"last id", 0, packets[7].ID, ""},
+		{"id dropped from the ring", 0, packets[1].ID, "45678"},
+		{"unknown id", 0, "missing", "45678"},
+	}
+	for _, tt := range tests {
+		if got := replayed(ch, tt.seq, tt.since); got != tt.want {
+			t.Errorf("%s: replayed %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHistoryAgeOnly(t *testing.T) {
+	ch := newHistoryChannel(newFakeCache(), 0, time.Hour)
+	write(t, ch, historyLimit+2)
+	c := newTestConn("u1", 1)
+	ch.(*channel).server.history.replay(ch, c, 1, "")
+	if len(c.packets) != historyLimit {
+		t.Errorf("replayed %d messages, want %d", len(c.packets), historyLimit)
+	}
+}
+
+func TestHistoryExpiry(t *testing.T) {
+	ch := newHistoryChannel(newFakeCache(), 10, 30*time.Millisecond)
+	write(t, ch, 3)
+	if got := replayed(ch, 1, ""); got != "23" {
+		t.Errorf("replayed %q, want %q", got, "23")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if got := replayed(ch, 1, ""); got != "" {
+		t.Errorf("replayed %q after the ttl, want nothing", got)
+	}
+	if got := ch.(*channel).server.history.sequence(ch); got != 3 {
+		t.Errorf("sequence = %d after the ttl, want 3", got)
+	}
+}
+
+func TestHistoryConditions(t *testing.T) {
+	ch := newHistoryChannel(newFakeCache(), 10, 0)
+	h := ch.(*channel).server.history
+	for i, cond := range []*Condition{nil, {EqIDs: []int64{2}}, {NeIDs: []int64{2}}} {
+		p := &Packet{Action: ActionMessage, Message: []byte(fmt.Sprint(i))}
+		h.record(ch, &Distribution{Packet: p, Condition: cond})
+	}
+	c := newTestConn("u2", 2)
+	h.replay(ch, c, 0, "missing")
+	if len(c.packets) != 2 || string(c.packets[0].Message) != "0" || string(c.packets[1].Message) != "1" {
+		t.Errorf("replayed %v, want messages 0 and 1", c.packets)
+	}
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	tests := []struct {
+		name string
+		ch   Channel
+	}{
+		{"without cache", newHistoryChannel(nil, 10, 0)},
+		{"without bounds", newHistoryChannel(newFakeCache(), 0, 0)},
+	}
+	for _, tt := range tests {
+		p := write(t, tt.ch, 1)[0]
+		if p.Sequence != 0 {
+			t.Errorf("%s: packet numbered %d", tt.name, p.Sequence)
+		}
+		if got := replayed(tt.ch, 0, "missing"); got != "" {
+			t.Errorf("%s: replayed %q", tt.name, got)
+		}
+	}
+}
+
+func TestHistoryNodesShareSequence(t *testing.T) {
+	c := newCounterCache()
+	a := New(Cache(c)).(*server).Namespace("chat").History(100, 0).Channels().Add("room").Get("room")
+	b := New(Cache(c)).(*server).Namespace("chat").History(100, 0).Channels().Add("room").Get("room")
+	var (
+		wg   stdsync.WaitGroup
+		mu   stdsync.Mutex
+		seen = make(map[uint64]int)
+	)
+	for _, ch := range []Channel{a, b, a, b} {
+		wg.Add(1)
+		go func(ch Channel) {
+			defer wg.Done()
+			for _, p := range write(t, ch, 20) {
+				mu.Lock()
+				seen[p.Sequence]++
+				mu.Unlock()
+			}
+		}(ch)
+	}
+	wg.Wait()
+	for seq := uint64(1); seq <= 80; seq++ {
+		if seen[seq] != 1 {
+			t.Errorf("sequence %d handed out %d times", seq, seen[seq])
+		}
+	}
+	if got := len(replayed(b, 0, "missing")); got == 0 {
+		t.Error("node b replayed nothing")
+	}
+}
@@ -1,5 +1,7 @@
 package sync
 
+import "time"
+
 type namespace struct {
 	namespace     string
 	aliases       []string
@@ -10,6 +12,9 @@ type namespace struct {
 	catch         HandlerFunc
 	handlers      []Handler
 	middleware    HandlerFuncs
+	historySize   int
+	historyTTL    time.Duration
+	patterns      bool
 	channels      Channels
 	config        NamespaceConfig
 }
@@ -52,6 +57,9 @@ func (v *namespace) Middleware(s ...HandlerFunc) Namespace {
 	return v
 }
 
+// Handle registers a handler for calls of the name which answers through the
+// connection itself. Handlers keep their signature for compatibility with
+// existing applications, calls expecting a response are registered with Reply
 func (v *namespace) Handle(s string, f HandlerFunc) Namespace {
 	if s != "" && f != nil {
 		h := &handler{
@@ -63,11 +71,39 @@ func (v *namespace) Handle(s string, f HandlerFunc) Namespace {
 	return v
 }
 
+// Reply registers a call handler whose response is sent back to the caller
+func (v *namespace) Reply(s string, f ReplyFunc) Namespace {
+	if s != "" && f != nil {
+		h := &handler{
+			name:  s,
+			reply: f,
+		}
+		v.handlers = append(v.handlers, h)
+	}
+	return v
+}
+
 func (v *namespace) Catch(f HandlerFunc) Namespace {
 	v.catch = f
 	return v
 }
 
+// History keeps the last size messages of each channel, or those younger
+// than ttl, for subscribers to replay, zero leaves a bound unset and
+// histories bounded by age only keep at most 1000 messages
+func (v *namespace) History(size int, ttl time.Duration) Namespace {
+	v.historySize = size
+	v.historyTTL = ttl
+	return v
+}
+
+// Patterns enables wildcard subscriptions, `*` and `#` are ordinary
+// characters of channel names unless enabled
+func (v *namespace) Patterns(b bool) Namespace {
+	v.patterns = b
+	return v
+}
+
 func (v *namespace) Channels() Channels {
 	return v.channels
 }
@@ -84,6 +120,9 @@ func (v *namespace) Config() NamespaceConfig {
 			catch:         v.catch,
 			handlers:      v.handlers,
 			middleware:    v.middleware,
+			historySize:   v.historySize,
+			historyTTL:    v.historyTTL,
+			patterns:      v.patterns,
 			channels:      v.channels,
 		}
 	}
@@ -3,6 +3,7 @@ package sync
 import (
 	"crypto/rand"
 	"fmt"
+	"time"
 
 	"github.com/vaniila/hyper/cache"
 	"github.com/vaniila/hyper/logger"
@@ -29,6 +30,20 @@ type Options struct {
 
 	// logger
 	Logger logger.Service
+
+	// PresenceInterval between presence heartbeats, zero disables cluster
+	// presence tracking
+	PresenceInterval time.Duration
+
+	// PresenceTTL after which members of nodes without heartbeat expire
+	PresenceTTL time.Duration
+
+	// AckTimeout to wait for the acknowledgement of signed packets, zero
+	// disables delivery tracking
+	AckTimeout time.Duration
+
+	// AckRetries is the number of times unacknowledged packets are resent
+	AckRetries int
 }
 
 func newID() string {
@@ -41,7 +56,11 @@ func newID() string {
 
 func newOptions(opts ...Option) Options {
 	opt := Options{
-		ID: newID(),
+		ID:               newID(),
+		PresenceInterval: 10 * time.Second,
+		PresenceTTL:      30 * time.Second,
+		AckTimeout:       5 * time.Second,
+		AckRetries:       3,
 	}
 	for _, o := range opts {
 		o(&opt)
@@ -86,3 +105,31 @@ func Logger(l logger.Service) Option {
 		o.Logger = l
 	}
 }
+
+// PresenceInterval to set the interval of presence heartbeats
+func PresenceInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.PresenceInterval = d
+	}
+}
+
+// PresenceTTL to set how long members of a silent node are kept
+func PresenceTTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.PresenceTTL = d
+	}
+}
+
+// AckTimeout to set how long signed packets wait for acknowledgement
+func AckTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.AckTimeout = d
+	}
+}
+
+// AckRetries to set how many times unacknowledged packets are resent
+func AckRetries(i int) Option {
+	return func(o *Options) {
+		o.AckRetries = i
+	}
+}
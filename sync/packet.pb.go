@@ -21,6 +21,8 @@ type Packet struct {
 	Call      string `protobuf:"bytes,50,opt,name=Call" json:"Call,omitempty"`
 	Message   []byte `protobuf:"bytes,60,opt,name=Message,proto3" json:"Message,omitempty"`
 	Error     string `protobuf:"bytes,70,opt,name=Error" json:"Error,omitempty"`
+	Sequence  uint64 `protobuf:"varint,80,opt,name=Sequence" json:"Sequence,omitempty"`
+	Since     string `protobuf:"bytes,90,opt,name=Since" json:"Since,omitempty"`
 }
 
 func (m *Packet) Reset()                    { *m = Packet{} }
@@ -84,6 +86,20 @@ func (m *Packet) GetError() string {
 	return ""
 }
 
+func (m *Packet) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *Packet) GetSince() string {
+	if m != nil {
+		return m.Since
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Packet)(nil), "sync.Packet")
 }
@@ -91,18 +107,19 @@ func init() {
 func init() { proto.RegisterFile("packet.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 194 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0xe2, 0x29, 0x48, 0x4c, 0xce,
-	0x4e, 0x2d, 0xd1, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x29, 0xae, 0xcc, 0x4b, 0x56, 0x3a,
-	0xc5, 0xc8, 0xc5, 0x16, 0x00, 0x16, 0x16, 0xe2, 0xe3, 0x62, 0xf2, 0x74, 0x91, 0x60, 0x54, 0x60,
-	0xd4, 0xe0, 0x0c, 0x62, 0xf2, 0x74, 0x11, 0x92, 0xe1, 0xe2, 0x0c, 0xce, 0x4c, 0xcf, 0x4b, 0x2c,
-	0x29, 0x2d, 0x4a, 0x95, 0xe0, 0x52, 0x60, 0xd4, 0xe0, 0x08, 0x42, 0x08, 0x08, 0x89, 0x71, 0xb1,
-	0x39, 0x26, 0x97, 0x64, 0xe6, 0xe7, 0x49, 0x88, 0x28, 0x30, 0x6a, 0xb0, 0x06, 0x41, 0x79, 0x20,
-	0x5d, 0x7e, 0x89, 0xb9, 0xa9, 0xc5, 0x05, 0x89, 0xc9, 0xa9, 0x12, 0x72, 0x60, 0xc3, 0x10, 0x02,
-	0x42, 0x12, 0x5c, 0xec, 0xce, 0x19, 0x89, 0x79, 0x79, 0xa9, 0x39, 0x12, 0x1a, 0x60, 0x39, 0x18,
-	0x57, 0x48, 0x88, 0x8b, 0xc5, 0x39, 0x31, 0x27, 0x47, 0xc2, 0x08, 0x2c, 0x0c, 0x66, 0x83, 0x54,
-	0xfb, 0xa6, 0x16, 0x17, 0x27, 0xa6, 0xa7, 0x4a, 0xd8, 0x28, 0x30, 0x6a, 0xf0, 0x04, 0xc1, 0xb8,
-	0x42, 0x22, 0x5c, 0xac, 0xae, 0x45, 0x45, 0xf9, 0x45, 0x12, 0x6e, 0x60, 0xe5, 0x10, 0x4e, 0x12,
-	0x1b, 0xd8, 0x67, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x3a, 0xec, 0x6d, 0x53, 0xe9, 0x00,
-	0x00, 0x00,
+	// 217 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x44, 0x90, 0xb1, 0x4b, 0x03, 0x31,
+	0x14, 0xc6, 0x49, 0xb9, 0x9e, 0xed, 0xa3, 0x38, 0x3c, 0x8a, 0x3c, 0x44, 0x24, 0x38, 0x65, 0x72,
+	0xd0, 0xd5, 0x45, 0x5a, 0x85, 0x0e, 0x4a, 0xc9, 0x6d, 0x6e, 0x31, 0x3c, 0xea, 0xe1, 0x99, 0x3b,
+	0x93, 0x74, 0xf0, 0x7f, 0x77, 0x90, 0xbc, 0xda, 0xde, 0x96, 0xdf, 0xef, 0xfb, 0xbe, 0x0c, 0x0f,
+	0x16, 0x83, 0xf3, 0x9f, 0x9c, 0x6f, 0x87, 0xd8, 0xe7, 0x1e, 0xab, 0xf4, 0x13, 0xfc, 0xcd, 0xaf,
+	0x82, 0x7a, 0x2b, 0x1a, 0xcf, 0x61, 0xb2, 0x59, 0x93, 0xd2, 0xca, 0xcc, 0xed, 0x64, 0xb3, 0xc6,
+	0x2b, 0x98, 0x37, 0xed, 0x2e, 0xb8, 0xbc, 0x8f, 0x4c, 0xa0, 0x95, 0x99, 0xd9, 0x51, 0xe0, 0x05,
+	0xd4, 0x8f, 0x3e, 0xb7, 0x7d, 0xa0, 0xa5, 0x56, 0x66, 0x6a, 0xff, 0xa9, 0xac, 0x5e, 0xdd, 0x17,
+	0xa7, 0xc1, 0x79, 0xa6, 0x6b, 0xf9, 0x6c, 0x14, 0x48, 0x70, 0xb6, 0xfa, 0x70, 0x21, 0x70, 0x47,
+	0x46, 0xb2, 0x23, 0x22, 0x42, 0xb5, 0x72, 0x5d, 0x47, 0x77, 0xa2, 0xe5, 0x5d, 0xda, 0x2f, 0x9c,
+	0x92, 0xdb, 0x31, 0x3d, 0x68, 0x65, 0x16, 0xf6, 0x88, 0xb8, 0x84, 0xe9, 0x53, 0x8c, 0x7d, 0xa4,
+	0x67, 0xa9, 0x1f, 0x00, 0x2f, 0x61, 0xd6, 0xf0, 0xf7, 0x9e, 0x83, 0x67, 0xda, 0x6a, 0x65, 0x2a,
+	0x7b, 0xe2, 0xb2, 0x68, 0xda, 0x12, 0xbc, 0x1d, 0x16, 0x02, 0xef, 0xb5, 0xdc, 0xe2, 0xfe, 0x6f,
+	0x00, 0xc7, 0x9f, 0x35, 0xb7, 0x1b, 0x01, 0x00, 0x00,
 }
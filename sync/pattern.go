@@ -0,0 +1,105 @@
+package sync
+
+// channel names are split into segments on dots and slashes, a pattern
+// segment of `*` matches any one segment and a final `#` matches all the
+// remaining segments, including none
+const (
+	wildcardOne = "*"
+	wildcardAll = "#"
+)
+
+// pattern indexes the pattern channels of a namespace by segment so a
+// channel name only walks the branches it can match
+type pattern struct {
+	children map[string]*pattern
+	channels []Channel
+}
+
+func (v *pattern) insert(segs []string, ch Channel) {
+	node := v
+	for _, s := range segs {
+		if node.children == nil {
+			node.children = make(map[string]*pattern)
+		}
+		next, ok := node.children[s]
+		if !ok {
+			next = &pattern{}
+			node.children[s] = next
+		}
+		node = next
+	}
+	node.channels = append(node.channels, ch)
+}
+
+// remove deletes the channel and reports whether the node became empty
+func (v *pattern) remove(segs []string, ch Channel) bool {
+	if len(segs) == 0 {
+		for i, o := range v.channels {
+			if o == ch {
+				v.channels = append(v.channels[:i], v.channels[i+1:]...)
+				break
+			}
+		}
+	} else if next, ok := v.children[segs[0]]; ok && next.remove(segs[1:], ch) {
+		delete(v.children, segs[0])
+	}
+	return len(v.channels) == 0 && len(v.children) == 0
+}
+
+func (v *pattern) match(segs []string, list *[]Channel) {
+	if next, ok := v.children[wildcardAll]; ok {
+		*list = append(*list, next.channels...)
+	}
+	if len(segs) == 0 {
+		*list = append(*list, v.channels...)
+		return
+	}
+	if next, ok := v.children[segs[0]]; ok {
+		next.match(segs[1:], list)
+	}
+	if next, ok := v.children[wildcardOne]; ok && segs[0] != wildcardOne {
+		next.match(segs[1:], list)
+	}
+}
+
+func segments(name string) []string {
+	var segs []string
+	start := 0
+	for i := 0; i < len(name); i++ {
+		if name[i] == '.' || name[i] == '/' {
+			segs = append(segs, name[start:i])
+			start = i + 1
+		}
+	}
+	return append(segs, name[start:])
+}
+
+// patternOf tells whether the name is a pattern of the namespace, names
+// are literal in namespaces without wildcard subscriptions
+func patternOf(n Namespace, name string) bool {
+	return n != nil && n.Config().Patterns() && isPattern(name)
+}
+
+func isPatternChannel(ch Channel) bool {
+	return patternOf(ch.Namespace(), ch.Name())
+}
+
+func isPattern(name string) bool {
+	for _, s := range segments(name) {
+		if s == wildcardOne || s == wildcardAll {
+			return true
+		}
+	}
+	return false
+}
+
+// validPattern checks that `#` only ends a pattern
+func validPattern(name string) bool {
+	segs := segments(name)
+	for i, s := range segs {
+		if s == wildcardAll && i != len(segs)-1 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package sync
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func newChannels(names ...string) *channels {
+	v := &channels{namespace: &namespace{patterns: true}, channels: make(map[string]Channel)}
+	for _, name := range names {
+		v.Add(name)
+	}
+	return v
+}
+
+func names(list []Channel) string {
+	var s []string
+	for _, c := range list {
+		s = append(s, c.Name())
+	}
+	sort.Strings(s)
+	return strings.Join(s, " ")
+}
+
+func TestSegments(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"room", []string{"room"}},
+		{"room.1", []string{"room", "1"}},
+		{"room/1.typing", []string{"room", "1", "typing"}},
+		{"room.", []string{"room", ""}},
+		{"", []string{""}},
+	}
+	for _, tt := range tests {
+		if got := segments(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("segments(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsPattern(t *testing.T) {
+	tests := []struct {
+		in          string
+		pattern, ok bool
+	}{
+		{"room.1", false, true},
+		{"room*", false, true},
+		{"room.*", true, true},
+		{"*.typing", true, true},
+		{"room.#", true, true},
+		{"#", true, true},
+		{"room.#.typing", true, false},
+		{"#.#", true, false},
+	}
+	for _, tt := range tests {
+		if got := isPattern(tt.in); got != tt.pattern {
+			t.Errorf("isPattern(%q) = %v, want %v", tt.in, got, tt.pattern)
+		}
+		if got := validPattern(tt.in); got != tt.ok {
+			t.Errorf("validPattern(%q) = %v, want %v", tt.in, got, tt.ok)
+		}
+	}
+}
+
+func TestChannelsMatch(t *testing.T) {
+	chs := newChannels(
+		"room.1",
+		"room.*",
+		"room.#",
+		"room.*.typing",
+		"*.1",
+		"#",
+		"user/*",
+	)
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"room.1", "# *.1 room.# room.* room.1"},
+		{"room.2", "# room.# room.*"},
+		{"room", "# room.#"},
+		{"room.1.typing", "# room.# room.*.typing"},
+		{"room.1.seen", "# room.#"},
+		{"user.1", "# *.1 user/*"},
+		{"user/1/typing", "#"},
+		{"lobby", "#"},
+		{"room.*", "# room.# room.*"},
+	}
+	for _, tt := range tests {
+		if got := names(chs.Match(tt.name)); got != tt.want {
+			t.Errorf("Match(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChannelsDelPattern(t *testing.T) {
+	chs := newChannels("room.*", "room.#", "room.*.typing")
+	chs.Del("room.*")
+	if got := names(chs.Match("room.1")); got != "room.#" {
+		t.Errorf("Match after delete = %q, want %q", got, "room.#")
+	}
+	chs.Del("room.*.typing")
+	chs.Del("room.#")
+	if len(chs.patterns.children) != 0 {
+		t.Errorf("pattern index keeps %d branches", len(chs.patterns.children))
+	}
+}
+
+func TestSubscriptionsKeepPatternsApart(t *testing.T) {
+	ns := &namespace{namespace: "chat", patterns: true}
+	room := &channel{namespace: ns, name: "room.1"}
+	rooms := &channel{namespace: ns, name: "room.*"}
+	subs := &subscriptions{}
+	subs.Add(room).Add(rooms).Add(rooms)
+	if !subs.Has("chat", "room.1") || subs.Has("chat", "room.*") {
+		t.Error("channels and patterns are mixed")
+	}
+	if !subs.Follows("chat", "room.*") || subs.Follows("chat", "room.1") {
+		t.Error("patterns and channels are mixed")
+	}
+	if len(subs.List()) != 1 || len(subs.Patterns()) != 1 {
+		t.Errorf("%d channels and %d patterns, want one each", len(subs.List()), len(subs.Patterns()))
+	}
+	subs.Del(rooms)
+	if subs.Follows("chat", "room.*") || !subs.Has("chat", "room.1") {
+		t.Error("deleting the pattern changed the channels")
+	}
+}
+
+func TestPatternsDisabled(t *testing.T) {
+	chs := &channels{namespace: &namespace{}, channels: make(map[string]Channel)}
+	chs.Add("room.*")
+	if got := names(chs.Match("room.1")); got != "" {
+		t.Errorf("Match(%q) = %q, want no channel", "room.1", got)
+	}
+	if got := names(chs.Match("room.*")); got != "room.*" {
+		t.Errorf("Match(%q) = %q, want the literal channel", "room.*", got)
+	}
+	subs := &subscriptions{}
+	subs.Add(chs.Get("room.*"))
+	if !subs.Has("", "room.*") || len(subs.Patterns()) != 0 {
+		t.Error("a literal channel was kept as pattern")
+	}
+}
+
+func TestPatternAuthorizesChannels(t *testing.T) {
+	s := New().(*server)
+	n := s.Namespace("chat").Patterns(true).Authorize(func(name string, c Context) error {
+		if name == "room.secret" {
+			return ChannelUnauthorized.Fill("chat", name)
+		}
+		return nil
+	})
+	tests := []struct {
+		channel string
+		action  int32
+	}{
+		{"room.*", ActionSubscribeSuccessful},
+		{"room.secret", ActionSubscribeFailure},
+	}
+	for _, tt := range tests {
+		c := newTestConn("u1", 1)
+		s.HandleSubscribe(&Packet{Action: ActionSubscribe, Namespace: "chat", Channel: tt.channel}, n, c)
+		if len(c.packets) != 1 || c.packets[0].Action != tt.action {
+			t.Errorf("subscribing %q wrote %v, want action %d", tt.channel, c.packets, tt.action)
+		}
+	}
+
+	c := newTestConn("u2", 2)
+	s.HandleSubscribe(&Packet{Action: ActionSubscribe, Namespace: "chat", Channel: "room.*"}, n, c)
+	for _, name := range []string{"room.public", "room.secret"} {
+		n.Channels().Add(name)
+		if err := s.Subscribe(&Distribution{Packet: &Packet{Action: ActionMessage, Namespace: "chat", Channel: name, Message: []byte(name)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got []string
+	for _, p := range c.packets {
+		if p.Action == ActionMessage {
+			got = append(got, string(p.Message))
+		}
+	}
+	if strings.Join(got, " ") != "room.public" {
+		t.Errorf("pattern follower received %v, want only room.public", got)
+	}
+}
@@ -0,0 +1,464 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vaniila/hyper/message"
+)
+
+// Member is an identity subscribed to a channel
+type Member struct {
+	ID  int    `json:"id,omitempty"`
+	Key string `json:"key,omitempty"`
+}
+
+type channelKey struct {
+	namespace, channel string
+}
+
+// presenceEntry lists the members a node holds in a channel
+type presenceEntry struct {
+	Members []Member `json:"members"`
+	Expires int64    `json:"expires"`
+}
+
+// presenceMessage announces the members a node holds in a channel, it is
+// sent on every change and as heartbeat
+type presenceMessage struct {
+	Node      string   `json:"node"`
+	Namespace string   `json:"namespace"`
+	Channel   string   `json:"channel"`
+	Members   []Member `json:"members"`
+}
+
+// presenceRoster is the cache entry of a node listing its members of every
+// channel, only the node itself writes it
+type presenceRoster struct {
+	Channels []*presenceMessage `json:"channels"`
+	Expires  int64              `json:"expires"`
+}
+
+// presence tracks channel members across the cluster, every node announces
+// its own members and entries of nodes which stop announcing expire
+type presence struct {
+	server   *server
+	node     string
+	topic    []byte
+	interval time.Duration
+	ttl      time.Duration
+	remote   map[channelKey]map[string]*presenceEntry
+	view     map[channelKey]map[Member]struct{}
+	loaded   map[channelKey]struct{}
+	own      map[channelKey][]Member
+	pending  map[channelKey]Channel
+	wake     chan struct{}
+	stop     message.Close
+	done     chan struct{}
+	sync.Mutex
+}
+
+func newPresence(s *server, interval, ttl time.Duration) *presence {
+	return &presence{
+		server:   s,
+		node:     s.id,
+		topic:    append(append([]byte{}, s.topic...), ":presence"...),
+		interval: interval,
+		ttl:      ttl,
+		remote:   make(map[channelKey]map[string]*presenceEntry),
+		view:     make(map[channelKey]map[Member]struct{}),
+		loaded:   make(map[channelKey]struct{}),
+		own:      make(map[channelKey][]Member),
+		pending:  make(map[channelKey]Channel),
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+func (v *presence) start() {
+	v.stop = v.server.message.Listen(v.topic, v.receive)
+	go func() {
+		ticker := time.NewTicker(v.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-v.done:
+				return
+			case <-v.wake:
+				v.flush()
+			case <-ticker.C:
+				v.heartbeat()
+				v.expire()
+			}
+		}
+	}()
+}
+
+// close withdraws the members of this node so other nodes see them leave
+// without waiting for expiry
+func (v *presence) close() {
+	select {
+	case <-v.done:
+		return
+	default:
+		close(v.done)
+	}
+	for _, ch := range v.channels() {
+		v.announce(ch, nil)
+	}
+	v.store()
+	if v.stop != nil {
+		v.stop()
+	}
+}
+
+// update queues the channel for announcing its local members, the broker
+// and cache are left to the presence worker so subscribing never waits on
+// them
+func (v *presence) update(ch Channel) {
+	v.Lock()
+	v.pending[keyOf(ch)] = ch
+	v.Unlock()
+	select {
+	case v.wake <- struct{}{}:
+	default:
+	}
+}
+
+// flush announces the channels which changed since the last flush
+func (v *presence) flush() {
+	v.Lock()
+	pending := v.pending
+	v.pending = make(map[channelKey]Channel)
+	v.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+	for _, ch := range pending {
+		v.announce(ch, localMembers(ch))
+	}
+	v.store()
+	for key := range pending {
+		v.refresh(key)
+	}
+}
+
+func (v *presence) heartbeat() {
+	for _, ch := range v.channels() {
+		if members := localMembers(ch); len(members) > 0 {
+			v.announce(ch, members)
+		}
+	}
+	v.store()
+}
+
+func (v *presence) announce(ch Channel, members []Member) {
+	key := keyOf(ch)
+	m := &presenceMessage{
+		Node:      v.node,
+		Namespace: key.namespace,
+		Channel:   key.channel,
+		Members:   members,
+	}
+	v.Lock()
+	if len(members) == 0 {
+		delete(v.own, key)
+	} else {
+		v.own[key] = members
+	}
+	v.Unlock()
+	b, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	if err := v.server.message.Emit(v.topic, b); err != nil && v.server.logger != nil {
+		v.server.logger.Error(fmt.Sprintf("sync presence announce: %v", err))
+	}
+}
+
+// store writes the roster of this node to the cache, so nodes which just
+// started know the members before any heartbeat arrives, and registers the
+// node in the node index, an index update lost to a concurrent writer is
+// repaired by the next heartbeat
+func (v *presence) store() {
+	if v.server.cache == nil {
+		return
+	}
+	now := time.Now()
+	roster := &presenceRoster{Expires: now.Add(v.ttl).UnixNano()}
+	v.Lock()
+	for key, members := range v.own {
+		roster.Channels = append(roster.Channels, &presenceMessage{
+			Namespace: key.namespace,
+			Channel:   key.channel,
+			Members:   members,
+		})
+	}
+	v.Unlock()
+	if b, err := json.Marshal(roster); err == nil {
+		v.server.cache.Set(rosterKey(v.node), b, v.ttl)
+	}
+	nodes := v.nodes()
+	for node, expires := range nodes {
+		if expires < now.UnixNano() {
+			delete(nodes, node)
+		}
+	}
+	if len(roster.Channels) == 0 {
+		delete(nodes, v.node)
+	} else {
+		nodes[v.node] = roster.Expires
+	}
+	if b, err := json.Marshal(nodes); err == nil {
+		v.server.cache.Set(nodesKey, b, 0)
+	}
+}
+
+// load reads the members of other nodes from their cache rosters the first
+// time a channel is seen, later changes arrive through the message broker
+func (v *presence) load(key channelKey) {
+	v.Lock()
+	_, ok := v.loaded[key]
+	v.loaded[key] = struct{}{}
+	v.Unlock()
+	if ok || v.server.cache == nil {
+		return
+	}
+	now := time.Now().UnixNano()
+	found := make(map[string]*presenceEntry)
+	for node, expires := range v.nodes() {
+		if node == v.node || expires < now {
+			continue
+		}
+		roster := new(presenceRoster)
+		if b, err := v.server.cache.Get(rosterKey(node)); err != nil || len(b) == 0 || json.Unmarshal(b, roster) != nil {
+			continue
+		}
+		for _, m := range roster.Channels {
+			if m.Namespace == key.namespace && m.Channel == key.channel && len(m.Members) > 0 && roster.Expires >= now {
+				found[node] = &presenceEntry{Members: m.Members, Expires: roster.Expires}
+			}
+		}
+	}
+	if len(found) == 0 {
+		return
+	}
+	v.Lock()
+	defer v.Unlock()
+	entries, ok := v.remote[key]
+	if !ok {
+		entries = make(map[string]*presenceEntry)
+		v.remote[key] = entries
+	}
+	for node, e := range found {
+		if _, known := entries[node]; !known {
+			entries[node] = e
+		}
+	}
+}
+
+func (v *presence) nodes() map[string]int64 {
+	nodes := make(map[string]int64)
+	if b, err := v.server.cache.Get(nodesKey); err == nil && len(b) > 0 {
+		json.Unmarshal(b, &nodes)
+	}
+	return nodes
+}
+
+func (v *presence) receive(b []byte) {
+	m := new(presenceMessage)
+	if err := json.Unmarshal(b, m); err != nil || m.Node == v.node {
+		return
+	}
+	key := channelKey{m.Namespace, m.Channel}
+	v.Lock()
+	entries, ok := v.remote[key]
+	if !ok {
+		entries = make(map[string]*presenceEntry)
+		v.remote[key] = entries
+	}
+	if len(m.Members) == 0 {
+		delete(entries, m.Node)
+	} else {
+		entries[m.Node] = &presenceEntry{
+			Members: m.Members,
+			Expires: time.Now().Add(v.ttl).UnixNano(),
+		}
+	}
+	if len(entries) == 0 {
+		delete(v.remote, key)
+	}
+	v.Unlock()
+	v.refresh(key)
+}
+
+// expire drops entries of nodes which stopped sending heartbeats
+func (v *presence) expire() {
+	now := time.Now().UnixNano()
+	var keys []channelKey
+	v.Lock()
+	for key, entries := range v.remote {
+		for node, e := range entries {
+			if e.Expires < now {
+				delete(entries, node)
+				keys = append(keys, key)
+			}
+		}
+		if len(entries) == 0 {
+			delete(v.remote, key)
+		}
+	}
+	v.Unlock()
+	for _, key := range keys {
+		v.refresh(key)
+	}
+}
+
+// members lists the distinct members of a channel across the cluster
+func (v *presence) members(key channelKey) []Member {
+	v.load(key)
+	set := make(map[Member]struct{})
+	if ch := v.channel(key); ch != nil {
+		for _, m := range localMembers(ch) {
+			set[m] = struct{}{}
+		}
+	}
+	now := time.Now().UnixNano()
+	v.Lock()
+	for _, e := range v.remote[key] {
+		if e.Expires >= now {
+			for _, m := range e.Members {
+				set[m] = struct{}{}
+			}
+		}
+	}
+	v.Unlock()
+	return sortMembers(set)
+}
+
+// refresh compares the members of a channel with the last known ones and
+// tells local subscribers who joined and left
+func (v *presence) refresh(key channelKey) {
+	current := make(map[Member]struct{})
+	for _, m := range v.members(key) {
+		current[m] = struct{}{}
+	}
+	v.Lock()
+	previous := v.view[key]
+	if len(current) == 0 {
+		delete(v.view, key)
+	} else {
+		v.view[key] = current
+	}
+	v.Unlock()
+	ch := v.channel(key)
+	if ch == nil {
+		return
+	}
+	for m := range current {
+		if _, ok := previous[m]; !ok {
+			deliver(ch, ActionPresenceJoin, m)
+		}
+	}
+	for m := range previous {
+		if _, ok := current[m]; !ok {
+			deliver(ch, ActionPresenceLeave, m)
+		}
+	}
+}
+
+// channel finds the local channel without creating namespaces
+func (v *presence) channel(key channelKey) Channel {
+	v.server.RLock()
+	n, ok := v.server.nsmap[key.namespace]
+	v.server.RUnlock()
+	if !ok {
+		return nil
+	}
+	return n.Channels().Get(key.channel)
+}
+
+func (v *presence) channels() []Channel {
+	var list []Channel
+	v.server.RLock()
+	namespaces := v.server.namespaces
+	v.server.RUnlock()
+	for _, n := range namespaces {
+		for _, ch := range n.Channels().List() {
+			if isPatternChannel(ch) {
+				continue
+			}
+			list = append(list, ch)
+		}
+	}
+	return list
+}
+
+func localMembers(ch Channel) []Member {
+	set := make(map[Member]struct{})
+	for _, c := range ch.NodeSubscribers() {
+		if m, ok := memberOf(c); ok {
+			set[m] = struct{}{}
+		}
+	}
+	return sortMembers(set)
+}
+
+// memberOf returns the member of a connection, anonymous connections are
+// not members
+func memberOf(c Context) (Member, bool) {
+	var m Member
+	id := c.Identity()
+	if id == nil || (!id.HasID() && !id.HasKey()) {
+		return m, false
+	}
+	if id.HasID() {
+		m.ID = id.GetID()
+	}
+	if id.HasKey() {
+		m.Key = id.GetKey()
+	}
+	return m, true
+}
+
+func deliver(ch Channel, action int32, m Member) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	for _, c := range ch.NodeSubscribers() {
+		c.Write(&Packet{
+			Action:    action,
+			Namespace: ch.Namespace().Config().Namespace(),
+			Channel:   ch.Name(),
+			Message:   b,
+		})
+	}
+}
+
+func keyOf(ch Channel) channelKey {
+	return channelKey{ch.Namespace().Config().Namespace(), ch.Name()}
+}
+
+func rosterKey(node string) []byte {
+	return []byte("sync:presence:node:" + node)
+}
+
+var nodesKey = []byte("sync:presence:nodes")
+
+func sortMembers(set map[Member]struct{}) []Member {
+	list := make([]Member, 0, len(set))
+	for m := range set {
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ID != list[j].ID {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
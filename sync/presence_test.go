@@ -0,0 +1,271 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	stdsync "sync"
+	"testing"
+	"time"
+
+	"github.com/vaniila/hyper/message"
+)
+
+// fakeBroker delivers messages synchronously to the listeners of a topic
+type fakeBroker struct {
+	listeners []*fakeListener
+	stdsync.Mutex
+}
+
+type fakeListener struct {
+	topic []byte
+	fn    message.Handler
+}
+
+func (v *fakeBroker) Start() error   { return nil }
+func (v *fakeBroker) Stop() error    { return nil }
+func (v *fakeBroker) String() string { return "fake broker" }
+
+func (v *fakeBroker) Emit(topic, b []byte) error {
+	v.Lock()
+	listeners := append([]*fakeListener(nil), v.listeners...)
+	v.Unlock()
+	for _, l := range listeners {
+		if bytes.Equal(l.topic, topic) {
+			l.fn(b)
+		}
+	}
+	return nil
+}
+
+func (v *fakeBroker) Listen(topic []byte, fn message.Handler) message.Close {
+	l := &fakeListener{topic, fn}
+	v.Lock()
+	v.listeners = append(v.listeners, l)
+	v.Unlock()
+	return func() {
+		v.Lock()
+		defer v.Unlock()
+		for i, o := range v.listeners {
+			if o == l {
+				v.listeners = append(v.listeners[:i], v.listeners[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// fakeCache keeps entries in memory and honours their ttl
+type fakeCache struct {
+	entries map[string]fakeEntry
+	stdsync.Mutex
+}
+
+type fakeEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{entries: make(map[string]fakeEntry)}
+}
+
+func (v *fakeCache) Start() error   { return nil }
+func (v *fakeCache) Stop() error    { return nil }
+func (v *fakeCache) String() string { return "fake cache" }
+
+func (v *fakeCache) Set(key, b []byte, ttl time.Duration) error {
+	v.Lock()
+	defer v.Unlock()
+	e := fakeEntry{data: append([]byte(nil), b...)}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	v.entries[string(key)] = e
+	return nil
+}
+
+func (v *fakeCache) Get(key []byte) ([]byte, error) {
+	v.Lock()
+	defer v.Unlock()
+	e, ok := v.entries[string(key)]
+	if !ok || (!e.expires.IsZero() && time.Now().After(e.expires)) {
+		return nil, nil
+	}
+	return e.data, nil
+}
+
+type testIdentity struct {
+	id  int
+	key string
+}
+
+func (v *testIdentity) HasID() bool     { return v.id != 0 }
+func (v *testIdentity) GetID() int      { return v.id }
+func (v *testIdentity) SetID(i int)     { v.id = i }
+func (v *testIdentity) HasKey() bool    { return v.key != "" }
+func (v *testIdentity) GetKey() string  { return v.key }
+func (v *testIdentity) SetKey(s string) { v.key = s }
+
+// testConn records the packets written to it
+type testConn struct {
+	connContext
+	name    string
+	id      *testIdentity
+	subs    *subscriptions
+	packets []*Packet
+	stdsync.Mutex
+}
+
+func newTestConn(name string, id int) *testConn {
+	return &testConn{name: name, id: &testIdentity{id: id}, subs: &subscriptions{}}
+}
+
+func (v *testConn) Identity() Identity           { return v.id }
+func (v *testConn) MachineID() string            { return v.name }
+func (v *testConn) ProcessID() string            { return v.name }
+func (v *testConn) Context() context.Context     { return context.Background() }
+func (v *testConn) Subscriptions() Subscriptions { return v.subs }
+
+func (v *testConn) Write(p *Packet) error {
+	v.Lock()
+	defer v.Unlock()
+	v.packets = append(v.packets, p)
+	return nil
+}
+
+// events lists the presence changes written to the connection
+func (v *testConn) events() []string {
+	v.Lock()
+	defer v.Unlock()
+	var list []string
+	for _, p := range v.packets {
+		m := new(Member)
+		json.Unmarshal(p.Message, m)
+		switch p.Action {
+		case ActionPresenceJoin:
+			list = append(list, fmt.Sprintf("join %d", m.ID))
+		case ActionPresenceLeave:
+			list = append(list, fmt.Sprintf("leave %d", m.ID))
+		}
+	}
+	return list
+}
+
+func eventually(t *testing.T, what string, fn func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !fn() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func memberIDs(list []Member) string {
+	s := fmt.Sprint(len(list))
+	for _, m := range list {
+		s += fmt.Sprintf(" %d", m.ID)
+	}
+	return s
+}
+
+func has(list []string, s string) bool {
+	for _, o := range list {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}
+
+func newPresenceNode(id string, m message.Service, c *fakeCache) *server {
+	s := New(
+		ID(id),
+		Message(m),
+		Cache(c),
+		PresenceInterval(20*time.Millisecond),
+		PresenceTTL(80*time.Millisecond),
+	).(*server)
+	s.Namespace("chat")
+	s.Start()
+	return s
+}
+
+func TestPresenceJoinAndLeave(t *testing.T) {
+	broker, cache := &fakeBroker{}, newFakeCache()
+	a := newPresenceNode("a", broker, cache)
+	b := newPresenceNode("b", broker, cache)
+	defer a.presence.close()
+	defer b.presence.close()
+	cha := a.Namespace("chat").Channels().Add("room").Get("room")
+	chb := b.Namespace("chat").Channels().Add("room").Get("room")
+	u1, u2 := newTestConn("u1", 1), newTestConn("u2", 2)
+
+	cha.Subscribe(u1)
+	eventually(t, "the local join", func() bool { return has(u1.events(), "join 1") })
+	chb.Subscribe(u2)
+	eventually(t, "the remote join", func() bool { return has(u1.events(), "join 2") })
+	if got := memberIDs(cha.Members()); got != "2 1 2" {
+		t.Errorf("members on a = %s, want 2 1 2", got)
+	}
+	eventually(t, "members on b", func() bool { return memberIDs(chb.Members()) == "2 1 2" })
+
+	chb.Unsubscribe(u2)
+	eventually(t, "the remote leave", func() bool { return has(u1.events(), "leave 2") })
+	if got := memberIDs(cha.Members()); got != "1 1" {
+		t.Errorf("members on a = %s, want 1 1", got)
+	}
+
+	// stopping a node withdraws its members at once
+	chb.Subscribe(u2)
+	eventually(t, "the second join", func() bool { return memberIDs(cha.Members()) == "2 1 2" })
+	b.presence.close()
+	if got := memberIDs(cha.Members()); got != "1 1" {
+		t.Errorf("members on a after b stopped = %s, want 1 1", got)
+	}
+}
+
+func TestPresenceExpiry(t *testing.T) {
+	broker, cache := &fakeBroker{}, newFakeCache()
+	a := newPresenceNode("a", broker, cache)
+	defer a.presence.close()
+	cha := a.Namespace("chat").Channels().Add("room").Get("room")
+	u1 := newTestConn("u1", 1)
+	cha.Subscribe(u1)
+
+	// a node which announces once and then crashes without withdrawing
+	b, _ := json.Marshal(&presenceMessage{Node: "ghost", Namespace: "chat", Channel: "room", Members: []Member{{ID: 9}}})
+	broker.Emit(a.presence.topic, b)
+	if got := memberIDs(cha.Members()); got != "2 1 9" {
+		t.Fatalf("members = %s, want 2 1 9", got)
+	}
+	eventually(t, "the ghost to join", func() bool { return has(u1.events(), "join 9") })
+	eventually(t, "the ghost to expire", func() bool { return has(u1.events(), "leave 9") })
+	if got := memberIDs(cha.Members()); got != "1 1" {
+		t.Errorf("members after expiry = %s, want 1 1", got)
+	}
+}
+
+func TestPresenceLoadsRosters(t *testing.T) {
+	broker, cache := &fakeBroker{}, newFakeCache()
+	a := newPresenceNode("a", broker, cache)
+	defer a.presence.close()
+	cha := a.Namespace("chat").Channels().Add("room").Get("room")
+	cha.Subscribe(newTestConn("u1", 1))
+	cha.Subscribe(newTestConn("anonymous", 0))
+	eventually(t, "the roster", func() bool {
+		b, _ := cache.Get(rosterKey("a"))
+		return len(b) > 0
+	})
+
+	// a node started later learns the members from the cache
+	d := newPresenceNode("d", broker, cache)
+	defer d.presence.close()
+	chd := d.Namespace("chat").Channels().Add("room").Get("room")
+	if got := memberIDs(chd.Members()); got != "1 1" {
+		t.Errorf("members on d = %s, want 1 1", got)
+	}
+}
@@ -0,0 +1,108 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vaniila/hyper/fault"
+)
+
+var errDenied = fault.New("denied").SetStatus(409)
+
+func newReplyNamespace(catch HandlerFunc) (*server, Namespace, *testConn) {
+	s := New().(*server)
+	n := s.Namespace("rpc").
+		Catch(catch).
+		Handle("notify", func([]byte, Channel, Context) {}).
+		Reply("echo", func(b []byte, ch Channel, c Context) ([]byte, error) {
+			return append(b, '!'), nil
+		}).
+		Reply("deny", func([]byte, Channel, Context) ([]byte, error) {
+			return []byte("ignored"), errDenied
+		}).
+		Reply("fail", func([]byte, Channel, Context) ([]byte, error) {
+			return nil, errors.New("plain failure")
+		}).
+		Reply("panic", func([]byte, Channel, Context) ([]byte, error) {
+			panic("boom")
+		})
+	c := newTestConn("u1", 1)
+	c.subs.Add(n.Channels().Add("room").Get("room"))
+	return s, n, c
+}
+
+var actionNames = map[int32]string{
+	ActionMessageSuccessful: "successful",
+	ActionMessageFailure:    "failure",
+	ActionReply:             "reply",
+	ActionReplyFailure:      "reply-failure",
+}
+
+// summary describes a written packet by its id, action, message and error
+func summary(p *Packet) string {
+	s := fmt.Sprintf("%s %s %s", p.GetID(), actionNames[p.GetAction()], p.GetMessage())
+	if p.GetError() != "" {
+		s += " error"
+	}
+	return s
+}
+
+func TestHandleMessageReply(t *testing.T) {
+	tests := []struct {
+		name      string
+		call      string
+		signature bool
+		want      []string
+		err       string
+	}{
+		{"reply", "echo", false, []string{"42 reply hi!"}, ""},
+		{"signed reply", "echo", true, []string{"42 reply hi!", "42 successful "}, ""},
+		{"fault", "deny", false, []string{"42 reply-failure  error"}, errDenied.JsonString()},
+		{"plain error", "fail", false, []string{"42 reply-failure  error"}, "plain failure"},
+		{"recovered", "panic", true, []string{"42 reply-failure  error", "42 failure  error"}, "could not be handled"},
+		{"handler without reply", "notify", false, nil, ""},
+		{"unknown call", "missing", false, nil, ""},
+	}
+	for _, tt := range tests {
+		s, n, c := newReplyNamespace(nil)
+		s.HandleMessage(&Packet{
+			ID:        "42",
+			Action:    ActionMessage,
+			Namespace: "rpc",
+			Channel:   "room",
+			Call:      tt.call,
+			Message:   []byte("hi"),
+			Signature: tt.signature,
+		}, n, c)
+		var got []string
+		for _, p := range c.packets {
+			got = append(got, summary(p))
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: wrote %q, want %q", tt.name, got, tt.want)
+			continue
+		}
+		if len(c.packets) > 0 && !strings.Contains(c.packets[0].GetError(), tt.err) {
+			t.Errorf("%s: error %s, want %s", tt.name, c.packets[0].GetError(), tt.err)
+		}
+		if len(c.packets) > 0 && c.packets[0].GetCall() != tt.call {
+			t.Errorf("%s: reply to call %q, want %q", tt.name, c.packets[0].GetCall(), tt.call)
+		}
+	}
+}
+
+func TestHandleMessageReplyCatch(t *testing.T) {
+	var caught string
+	s, n, c := newReplyNamespace(func(b []byte, ch Channel, c Context) {
+		caught = fmt.Sprintf("%s %s", b, ch.Name())
+	})
+	s.HandleMessage(&Packet{ID: "7", Action: ActionMessage, Namespace: "rpc", Channel: "room", Call: "panic", Message: []byte("hi")}, n, c)
+	if caught != "hi room" {
+		t.Errorf("catch received %q, want %q", caught, "hi room")
+	}
+	if len(c.packets) != 1 || c.packets[0].GetID() != "7" || c.packets[0].GetAction() != ActionReplyFailure {
+		t.Errorf("wrote %v, want a reply failure to 7", c.packets)
+	}
+}
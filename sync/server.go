@@ -7,9 +7,12 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/websocket"
 	"github.com/vaniila/hyper/cache"
+	"github.com/vaniila/hyper/fault"
 	"github.com/vaniila/hyper/logger"
 	"github.com/vaniila/hyper/message"
 	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/websocket/keepalive"
+	"github.com/vaniila/hyper/websocket/outbound"
 )
 
 type server struct {
@@ -21,8 +24,14 @@ type server struct {
 	namespaces []Namespace
 	nsmap      map[string]Namespace
 	conns      map[string]Context
+	alive      keepalive.Config
+	out        outbound.Config
 	hookbo     HookFunc
 	hookac     HookFunc
+	hookuf     UndeliveredFunc
+	presence   *presence
+	history    *history
+	delivery   *delivery
 	stop       message.Close
 	sync.RWMutex
 }
@@ -48,10 +57,16 @@ func (v *server) Start() error {
 			v.Subscribe(d)
 		})
 	}()
+	if v.presence != nil {
+		v.presence.start()
+	}
 	return nil
 }
 
 func (v *server) Stop() error {
+	if v.presence != nil {
+		v.presence.close()
+	}
 	v.Lock()
 	v.nsmap = make(map[string]Namespace)
 	v.Unlock()
@@ -72,63 +87,104 @@ func (v *server) Subscribe(d *Distribution) error {
 		return InvalidPacket.Fill()
 	}
 	n := v.Namespace(d.Packet.GetNamespace())
-	cs := n.Channels()
-	if !cs.Has(d.Packet.GetChannel()) {
+	if patternOf(n, d.Packet.GetChannel()) {
+		return PatternNotWritable.Fill(d.Packet.GetNamespace(), d.Packet.GetChannel())
+	}
+	// the channel and the patterns matching it, connections following
+	// several of them receive the packet once
+	chs := n.Channels().Match(d.Packet.GetChannel())
+	if len(chs) == 0 {
 		return ChannelNotExist.Fill(d.Packet.GetChannel())
 	}
-	ch := cs.Get(d.Packet.GetChannel())
-	ns := ch.NodeSubscribers()
-	if d.Condition != nil {
-		if (len(d.Condition.EqIDs) > 0 || len(d.Condition.EqKeys) > 0) && (len(d.Condition.NeIDs) > 0 || len(d.Condition.NeKeys) > 0) {
-			return InvalidCondition.Fill(d.Condition)
-		}
-		if len(d.Condition.EqIDs) > 0 || len(d.Condition.EqKeys) > 0 {
-			im := make(map[int64]struct{})
-			km := make(map[string]struct{})
-			for _, i := range d.Condition.EqIDs {
-				im[i] = struct{}{}
+	if d.Condition != nil && (len(d.Condition.EqIDs) > 0 || len(d.Condition.EqKeys) > 0) && (len(d.Condition.NeIDs) > 0 || len(d.Condition.NeKeys) > 0) {
+		return InvalidCondition.Fill(d.Condition)
+	}
+	seen := make(map[string]struct{})
+	for _, ch := range chs {
+		// followers of a pattern were only authorized for the pattern, the
+		// channel itself is authorized before each delivery
+		pattern := ch.Name() != d.Packet.GetChannel()
+		for _, c := range ch.NodeSubscribers() {
+			u := fmt.Sprintf("%s-%s", c.MachineID(), c.ProcessID())
+			if _, ok := seen[u]; ok {
+				continue
 			}
-			for _, k := range d.Condition.EqKeys {
-				km[k] = struct{}{}
+			seen[u] = struct{}{}
+			if pattern && !authorized(n, d.Packet.GetChannel(), c) {
+				continue
 			}
-			for _, c := range ns {
-				if _, ok := im[int64(c.Identity().GetID())]; ok && c.Identity().HasID() {
-					c.Write(d.Packet)
-					continue
-				}
-				if _, ok := km[c.Identity().GetKey()]; ok && c.Identity().HasKey() {
-					c.Write(d.Packet)
-					continue
-				}
+			if matchCondition(d.Condition, c) {
+				c.Write(d.Packet)
 			}
-			return nil
 		}
-		if len(d.Condition.NeIDs) > 0 || len(d.Condition.NeKeys) > 0 {
-			im := make(map[int64]struct{})
-			km := make(map[string]struct{})
-			for _, i := range d.Condition.NeIDs {
-				im[i] = struct{}{}
-			}
-			for _, k := range d.Condition.NeKeys {
-				km[k] = struct{}{}
+	}
+	return nil
+}
+
+// authorized runs the authorize hook of the namespace for a channel
+func authorized(n Namespace, name string, c Context) bool {
+	if fn := n.Config().Authorize(); fn != nil {
+		return fn(name, c) == nil
+	}
+	return true
+}
+
+// matchCondition tests whether a connection is targeted by a condition,
+// conditions either include or exclude identities
+func matchCondition(d *Condition, c Context) bool {
+	if d == nil {
+		return true
+	}
+	id := c.Identity()
+	switch {
+	case len(d.EqIDs) > 0 || len(d.EqKeys) > 0:
+		for _, i := range d.EqIDs {
+			if id.HasID() && int64(id.GetID()) == i {
+				return true
 			}
-			for _, c := range ns {
-				if _, ok := im[int64(c.Identity().GetID())]; !ok && c.Identity().HasID() {
-					c.Write(d.Packet)
-					continue
-				}
-				if _, ok := km[c.Identity().GetKey()]; !ok && c.Identity().HasKey() {
-					c.Write(d.Packet)
-					continue
-				}
+		}
+		for _, k := range d.EqKeys {
+			if id.HasKey() && id.GetKey() == k {
+				return true
 			}
-			return nil
 		}
+		return false
+	case len(d.NeIDs) > 0 || len(d.NeKeys) > 0:
+		if id.HasID() && !containsID(d.NeIDs, int64(id.GetID())) {
+			return true
+		}
+		if id.HasKey() && !containsKey(d.NeKeys, id.GetKey()) {
+			return true
+		}
+		return false
 	}
-	for _, c := range ns {
-		c.Write(d.Packet)
+	return true
+}
+
+func containsID(ids []int64, i int64) bool {
+	for _, o := range ids {
+		if o == i {
+			return true
+		}
 	}
-	return nil
+	return false
+}
+
+func containsKey(keys []string, k string) bool {
+	for _, o := range keys {
+		if o == k {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *server) KeepAlive(c keepalive.Config) {
+	v.alive = c
+}
+
+func (v *server) Outbound(c outbound.Config) {
+	v.out = c
 }
 
 func (v *server) BeforeOpen(f HookFunc) {
@@ -139,13 +195,18 @@ func (v *server) AfterClose(f HookFunc) {
 	v.hookac = f
 }
 
+// Undelivered sets the hook of signed packets which were never acknowledged
+func (v *server) Undelivered(f UndeliveredFunc) {
+	v.hookuf = f
+}
+
 func (v *server) Handle(r router.Context, n *websocket.Conn) {
 	u := fmt.Sprintf("%s-%s", r.MachineID(), r.ProcessID())
 	c := &connection{
 		machineID:     r.MachineID(),
 		processID:     r.ProcessID(),
 		identity:      r.Identity(),
-		subscriptions: &subscriptions{make([]Channel, 0)},
+		subscriptions: &subscriptions{channels: make([]Channel, 0)},
 		ctx:           r.Context(),
 		req:           r.Req(),
 		res:           r.Res(),
@@ -156,8 +217,11 @@ func (v *server) Handle(r router.Context, n *websocket.Conn) {
 		message:       v.message,
 		logger:        v.logger,
 		server:        v,
+		delivery:      v.delivery,
 		conn:          n,
+		queue:         outbound.Start(n, v.out),
 	}
+	defer c.queue.Stop()
 	v.Lock()
 	v.conns[u] = c
 	v.Unlock()
@@ -170,16 +234,20 @@ func (v *server) Handle(r router.Context, n *websocket.Conn) {
 		v.Lock()
 		delete(v.conns, u)
 		v.Unlock()
+		v.delivery.drop(c)
 		if v.hookac != nil {
 			v.hookac(c)
 		}
 		c.AfterClose()
 	}()
+	monitor := keepalive.Start(n, v.alive, nil)
+	defer monitor.Stop()
 	for {
 		mt, message, err := n.ReadMessage()
 		if err != nil {
 			break
 		}
+		monitor.Touch()
 		v.Read(mt, message, c)
 	}
 }
@@ -195,6 +263,11 @@ func (v *server) Read(mt int, message []byte, c Context) {
 			})
 			return
 		}
+		// the client acknowledges a signed packet
+		if p.GetAction() == ActionMessageSuccessful {
+			v.delivery.ack(c, p.GetID())
+			return
+		}
 		v.RLock()
 		n, ok := v.nsmap[p.GetNamespace()]
 		v.RUnlock()
@@ -224,13 +297,25 @@ func (v *server) Read(mt int, message []byte, c Context) {
 
 func (v *server) HandleSubscribe(p *Packet, n Namespace, c Context) {
 	// checks if channel is already subscribed
-	if subscribed := c.Subscriptions().Has(p.GetNamespace(), p.GetChannel()); subscribed {
+	if subscribed(c, n, p) {
 		c.Write(&Packet{
 			Action: ActionSubscribeFailure,
 			Error:  ChannelAlreadySubscribed.Fill(p.GetNamespace(), p.GetChannel()).JsonString(),
 		})
 		return
 	}
+	// patterns are authorized by their name here and every matching channel
+	// is authorized again when a message is delivered
+	if patternOf(n, p.GetChannel()) && !validPattern(p.GetChannel()) {
+		c.Write(&Packet{
+			ID:        p.GetID(),
+			Action:    ActionSubscribeFailure,
+			Namespace: p.GetNamespace(),
+			Channel:   p.GetChannel(),
+			Error:     InvalidPattern.Fill(p.GetChannel()).JsonString(),
+		})
+		return
+	}
 	if fn := n.Config().Authorize(); fn != nil {
 		if err := fn(p.GetChannel(), c); err != nil {
 			c.Write(&Packet{
@@ -256,12 +341,25 @@ func (v *server) HandleSubscribe(p *Packet, n Namespace, c Context) {
 		Action:    ActionSubscribeSuccessful,
 		Namespace: p.GetNamespace(),
 		Channel:   p.GetChannel(),
+		Sequence:  v.history.sequence(ch),
 	})
+	// replays the messages the client missed, patterns have no history
+	if p.GetSequence() > 0 || p.GetSince() != "" {
+		v.history.replay(ch, c, p.GetSequence(), p.GetSince())
+	}
+}
+
+// subscribed tells whether the connection follows the channel or pattern
+func subscribed(c Context, n Namespace, p *Packet) bool {
+	if patternOf(n, p.GetChannel()) {
+		return c.Subscriptions().Follows(p.GetNamespace(), p.GetChannel())
+	}
+	return c.Subscriptions().Has(p.GetNamespace(), p.GetChannel())
 }
 
 func (v *server) HandleUnsubscribe(p *Packet, n Namespace, c Context) {
 	// checks if channel is not subscribed
-	if subscribed := c.Subscriptions().Has(p.GetNamespace(), p.GetChannel()); !subscribed {
+	if !subscribed(c, n, p) {
 		c.Write(&Packet{
 			Action: ActionUnsubscribeFailure,
 			Error:  ChannelNotSubscribed.Fill(p.GetNamespace(), p.GetChannel()).JsonString(),
@@ -285,8 +383,18 @@ func (v *server) HandleUnsubscribe(p *Packet, n Namespace, c Context) {
 }
 
 func (v *server) HandleMessage(p *Packet, n Namespace, c Context) {
+	// patterns only receive, messages go to concrete channels
+	if patternOf(n, p.GetChannel()) {
+		c.Write(&Packet{
+			ID:     p.GetID(),
+			Action: ActionMessageFailure,
+			Error:  PatternNotWritable.Fill(p.GetNamespace(), p.GetChannel()).JsonString(),
+		})
+		return
+	}
 	if subscribed := c.Subscriptions().Has(p.GetNamespace(), p.GetChannel()); !subscribed {
 		c.Write(&Packet{
+			ID:     p.GetID(),
 			Action: ActionMessageFailure,
 			Error:  ChannelNotSubscribed.Fill(p.GetNamespace(), p.GetChannel()).JsonString(),
 		})
@@ -294,11 +402,25 @@ func (v *server) HandleMessage(p *Packet, n Namespace, c Context) {
 	}
 	cs := n.Channels()
 	ch := cs.Get(p.GetChannel())
+	var calling bool
 	defer func() {
 		if err := recover(); err != nil {
 			if f := n.Config().Catch(); f != nil {
 				f(p.GetMessage(), ch, c)
 			}
+			// the caller still expects an answer
+			if calling {
+				v.reply(p, c, nil, MessageNotHandled.Fill(p.GetID()))
+			}
+			if p.GetSignature() {
+				c.Write(&Packet{
+					ID:        p.GetID(),
+					Action:    ActionMessageFailure,
+					Namespace: p.GetNamespace(),
+					Channel:   p.GetChannel(),
+					Error:     MessageNotHandled.Fill(p.GetID()).JsonString(),
+				})
+			}
 		}
 	}()
 	if md := n.Config().Middlewares(); len(md) > 0 {
@@ -308,11 +430,52 @@ func (v *server) HandleMessage(p *Packet, n Namespace, c Context) {
 	}
 	if hs := n.Config().Handlers(); len(hs) > 0 {
 		for _, h := range hs {
-			if p.GetCall() == h.Name() {
-				h.Func()(p.GetMessage(), ch, c)
+			if p.GetCall() != h.Name() {
+				continue
+			}
+			if fn := h.Func(); fn != nil {
+				fn(p.GetMessage(), ch, c)
 			}
+			if fn := h.Reply(); fn != nil {
+				calling = true
+				res, err := fn(p.GetMessage(), ch, c)
+				calling = false
+				v.reply(p, c, res, err)
+			}
+		}
+	}
+	// confirms the receipt of signed packets to the sender
+	if p.GetSignature() {
+		c.Write(&Packet{
+			ID:        p.GetID(),
+			Action:    ActionMessageSuccessful,
+			Namespace: p.GetNamespace(),
+			Channel:   p.GetChannel(),
+		})
+	}
+}
+
+// reply answers a call with the response or the fault of the handler,
+// correlated by the packet id of the call
+func (v *server) reply(p *Packet, c Context, res []byte, err error) {
+	r := &Packet{
+		ID:        p.GetID(),
+		Action:    ActionReply,
+		Namespace: p.GetNamespace(),
+		Channel:   p.GetChannel(),
+		Call:      p.GetCall(),
+		Message:   res,
+	}
+	if err != nil {
+		f, ok := fault.Is(err)
+		if !ok {
+			f = fault.Wrap(err)
 		}
+		r.Action = ActionReplyFailure
+		r.Message = nil
+		r.Error = f.JsonString()
 	}
+	c.Write(r)
 }
 
 func (v *server) Namespace(s string) Namespace {
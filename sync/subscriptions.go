@@ -1,41 +1,68 @@
 package sync
 
+// subscriptions of a connection, patterns are kept apart from channels so
+// following a pattern never grants access to a channel of the same name
 type subscriptions struct {
 	channels []Channel
+	patterns []Channel
 }
 
 func (v *subscriptions) Has(namespace, channel string) bool {
-	for _, o := range v.channels {
-		if o.Namespace().Config().Namespace() == namespace && o.Name() == channel {
-			return true
-		}
-	}
-	return false
+	return contains(v.channels, namespace, channel)
+}
+
+// Follows tells whether the pattern is subscribed
+func (v *subscriptions) Follows(namespace, pattern string) bool {
+	return contains(v.patterns, namespace, pattern)
 }
 
 func (v *subscriptions) Add(c Channel) Subscriptions {
+	list := &v.channels
+	if isPatternChannel(c) {
+		list = &v.patterns
+	}
 	var subscribed bool
-	for _, o := range v.channels {
+	for _, o := range *list {
 		if o == c {
 			subscribed = true
 			break
 		}
 	}
 	if !subscribed {
-		v.channels = append(v.channels, c)
+		*list = append(*list, c)
 	}
 	return v
 }
 
 func (v *subscriptions) Del(c Channel) Subscriptions {
-	for i, o := range v.channels {
-		if o == c {
-			v.channels = append(v.channels[:i], v.channels[i+1:]...)
-		}
-	}
+	v.channels = without(v.channels, c)
+	v.patterns = without(v.patterns, c)
 	return v
 }
 
 func (v *subscriptions) List() []Channel {
 	return v.channels
 }
+
+// Patterns lists the subscribed patterns
+func (v *subscriptions) Patterns() []Channel {
+	return v.patterns
+}
+
+func contains(list []Channel, namespace, name string) bool {
+	for _, o := range list {
+		if o.Namespace().Config().Namespace() == namespace && o.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func without(list []Channel, c Channel) []Channel {
+	for i, o := range list {
+		if o == c {
+			return append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
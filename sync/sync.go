@@ -9,11 +9,16 @@ import (
 	"github.com/vaniila/hyper/logger"
 	"github.com/vaniila/hyper/message"
 	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/websocket/keepalive"
+	"github.com/vaniila/hyper/websocket/outbound"
 )
 
 // HookFunc
 type HookFunc func(Context)
 
+// UndeliveredFunc
+type UndeliveredFunc func(*Packet, Context)
+
 // AuthorizeFunc
 type AuthorizeFunc func(string, Context) error
 
@@ -23,10 +28,15 @@ type HandlerFunc func([]byte, Channel, Context)
 // HandlerFuncs type
 type HandlerFuncs []HandlerFunc
 
+// ReplyFunc type answers a call, the response or error is sent back to the
+// caller
+type ReplyFunc func([]byte, Channel, Context) ([]byte, error)
+
 // Handler interface
 type Handler interface {
 	Name() string
 	Func() HandlerFunc
+	Reply() ReplyFunc
 }
 
 // Service interface
@@ -38,8 +48,11 @@ type Service interface {
 	Publish(*Distribution) error
 	Subscribe(*Distribution) error
 	Handle(router.Context, *websocket.Conn)
+	KeepAlive(keepalive.Config)
+	Outbound(outbound.Config)
 	BeforeOpen(HookFunc)
 	AfterClose(HookFunc)
+	Undelivered(UndeliveredFunc)
 	String() string
 }
 
@@ -52,7 +65,10 @@ type Namespace interface {
 	Authorize(AuthorizeFunc) Namespace
 	Middleware(...HandlerFunc) Namespace
 	Handle(string, HandlerFunc) Namespace
+	Reply(string, ReplyFunc) Namespace
 	Catch(HandlerFunc) Namespace
+	History(int, time.Duration) Namespace
+	Patterns(bool) Namespace
 	Channels() Channels
 	Config() NamespaceConfig
 }
@@ -66,6 +82,9 @@ type NamespaceConfig interface {
 	Middlewares() []HandlerFunc
 	Handlers() []Handler
 	Catch() HandlerFunc
+	HistorySize() int
+	HistoryTTL() time.Duration
+	Patterns() bool
 	Channels() Channels
 	Doc() string
 }
@@ -76,6 +95,7 @@ type Channels interface {
 	Get(string) Channel
 	Add(string) Channels
 	Del(string) Channels
+	Match(string) []Channel
 	List() map[string]Channel
 	Len() int
 }
@@ -85,6 +105,7 @@ type Channel interface {
 	Namespace() Namespace
 	Name() string
 	NodeSubscribers() []Context
+	Members() []Member
 	Has(Context) bool
 	Subscribe(Context) Channel
 	Unsubscribe(Context) Channel
@@ -127,9 +148,11 @@ type Identity interface {
 // Subscriptions interface
 type Subscriptions interface {
 	Has(string, string) bool
+	Follows(string, string) bool
 	Add(Channel) Subscriptions
 	Del(Channel) Subscriptions
 	List() []Channel
+	Patterns() []Channel
 }
 
 // Cache interface
@@ -167,5 +190,10 @@ func New(opts ...Option) Service {
 		nsmap:      make(map[string]Namespace),
 		conns:      make(map[string]Context),
 	}
+	s.history = newHistory(s)
+	s.delivery = newDelivery(s, o.AckTimeout, o.AckRetries)
+	if o.PresenceInterval > 0 {
+		s.presence = newPresence(s, o.PresenceInterval, o.PresenceTTL)
+	}
 	return s
 }
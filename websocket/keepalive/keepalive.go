@@ -0,0 +1,123 @@
+package keepalive
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Config for websocket connection liveness
+type Config struct {
+
+	// Interval between ping frames, zero disables pings
+	Interval time.Duration
+
+	// PongTimeout is how long to wait for a pong after a ping before the
+	// connection is considered dead
+	PongTimeout time.Duration
+
+	// IdleTimeout closes connections which have shown no activity for the
+	// duration, messages and pongs answering our pings both count as
+	// activity so listening clients stay connected, zero disables the reaper
+	IdleTimeout time.Duration
+}
+
+// Monitor keeps a single connection alive and reaps it once dead or idle
+type Monitor struct {
+	conn   *websocket.Conn
+	config Config
+	tick   func() error
+	last   int64
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Start monitors the connection, tick is invoked on every ping interval for
+// protocols with their own keepalive messages
+func Start(conn *websocket.Conn, config Config, tick func() error) *Monitor {
+	m := &Monitor{
+		conn:   conn,
+		config: config,
+		tick:   tick,
+		last:   time.Now().UnixNano(),
+		done:   make(chan struct{}),
+	}
+	if config.Interval > 0 {
+		wait := config.Interval + config.PongTimeout
+		if config.PongTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(wait))
+		}
+		conn.SetPongHandler(func(string) error {
+			m.Touch()
+			if config.PongTimeout > 0 {
+				return conn.SetReadDeadline(time.Now().Add(wait))
+			}
+			return nil
+		})
+	}
+	if config.Interval > 0 || config.IdleTimeout > 0 {
+		go m.run()
+	}
+	return m
+}
+
+// Touch records client activity, the monitor already counts pongs so
+// callers touch it for every message they read
+func (v *Monitor) Touch() {
+	atomic.StoreInt64(&v.last, time.Now().UnixNano())
+}
+
+// Stop ends monitoring, it does not close the connection
+func (v *Monitor) Stop() {
+	v.once.Do(func() {
+		close(v.done)
+	})
+}
+
+func (v *Monitor) run() {
+	var ping, idle <-chan time.Time
+	if v.config.Interval > 0 {
+		t := time.NewTicker(v.config.Interval)
+		defer t.Stop()
+		ping = t.C
+	}
+	if v.config.IdleTimeout > 0 {
+		t := time.NewTicker(v.config.IdleTimeout / 2)
+		defer t.Stop()
+		idle = t.C
+	}
+	for {
+		select {
+		case <-v.done:
+			return
+		case <-ping:
+			deadline := time.Now().Add(v.config.Interval)
+			if v.config.PongTimeout > 0 {
+				deadline = time.Now().Add(v.config.PongTimeout)
+			}
+			if err := v.conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				v.conn.Close()
+				return
+			}
+			if v.tick != nil {
+				if err := v.tick(); err != nil {
+					v.conn.Close()
+					return
+				}
+			}
+		case <-idle:
+			last := time.Unix(0, atomic.LoadInt64(&v.last))
+			if time.Since(last) >= v.config.IdleTimeout {
+				v.conn.WriteControl(
+					websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "idle timeout"),
+					time.Now().Add(time.Second),
+				)
+				v.conn.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package keepalive
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// serve monitors the server end of a websocket pair, the returned channel
+// receives the error ending the server read loop
+func serve(t *testing.T, config Config, tick func() error) (*websocket.Conn, chan error) {
+	ended := make(chan error, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer n.Close()
+		m := Start(n, config, tick)
+		defer m.Stop()
+		for {
+			if _, _, err := n.ReadMessage(); err != nil {
+				ended <- err
+				return
+			}
+			m.Touch()
+		}
+	}))
+	t.Cleanup(ts.Close)
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, ended
+}
+
+// listen reads the client end in the background, pings are counted and
+// answered when pong is set, the returned channel receives the read error
+func listen(c *websocket.Conn, pings *int32, pong bool) chan error {
+	c.SetPingHandler(func(s string) error {
+		atomic.AddInt32(pings, 1)
+		if pong {
+			return c.WriteControl(websocket.PongMessage, []byte(s), time.Now().Add(time.Second))
+		}
+		return nil
+	})
+	closed := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				closed <- err
+				return
+			}
+		}
+	}()
+	return closed
+}
+
+func alive(ended chan error, d time.Duration) error {
+	select {
+	case err := <-ended:
+		return err
+	case <-time.After(d):
+		return nil
+	}
+}
+
+func TestMonitorPing(t *testing.T) {
+	var ticks int32
+	c, ended := serve(t, Config{Interval: 20 * time.Millisecond, PongTimeout: 50 * time.Millisecond}, func() error {
+		atomic.AddInt32(&ticks, 1)
+		return nil
+	})
+	var pings int32
+	listen(c, &pings, true)
+	if err := alive(ended, 200*time.Millisecond); err != nil {
+		t.Fatalf("connection answering pings ended: %v", err)
+	}
+	if n := atomic.LoadInt32(&pings); n < 3 {
+		t.Errorf("received %d pings, want at least 3", n)
+	}
+	if n := atomic.LoadInt32(&ticks); n < 3 {
+		t.Errorf("ticked %d times, want at least 3", n)
+	}
+}
+
+func TestMonitorPongDeadline(t *testing.T) {
+	c, ended := serve(t, Config{Interval: 20 * time.Millisecond, PongTimeout: 30 * time.Millisecond}, nil)
+	var pings int32
+	listen(c, &pings, false)
+	select {
+	case err := <-ended:
+		if ne, ok := err.(interface{ Timeout() bool }); !ok || !ne.Timeout() {
+			t.Errorf("connection ended with %v, want a read timeout", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("connection without pongs is still alive")
+	}
+	if atomic.LoadInt32(&pings) == 0 {
+		t.Error("no ping was sent")
+	}
+}
+
+func TestMonitorTickFailure(t *testing.T) {
+	c, ended := serve(t, Config{Interval: 20 * time.Millisecond}, func() error {
+		return errors.New("write failed")
+	})
+	var pings int32
+	closed := listen(c, &pings, true)
+	select {
+	case <-ended:
+	case <-time.After(2 * time.Second):
+		t.Fatal("connection is still alive after the tick failed")
+	}
+	if err := <-closed; err == nil {
+		t.Error("client end was not closed")
+	}
+}
+
+func TestMonitorIdle(t *testing.T) {
+	config := Config{IdleTimeout: 40 * time.Millisecond}
+
+	// a silent client is reaped with a going away close frame
+	c, _ := serve(t, config, nil)
+	var pings int32
+	select {
+	case err := <-listen(c, &pings, true):
+		if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+			t.Errorf("idle connection closed with %v, want going away", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("idle connection is still open")
+	}
+
+	// messages keep an active client connected
+	c, ended := serve(t, config, nil)
+	listen(c, &pings, true)
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if err := c.WriteMessage(websocket.TextMessage, []byte("hi")); err != nil {
+			t.Fatalf("active connection closed: %v", err)
+		}
+		if err := alive(ended, 10*time.Millisecond); err != nil {
+			t.Fatalf("active connection ended: %v", err)
+		}
+	}
+}
+
+func TestMonitorStop(t *testing.T) {
+	m := Start(nil, Config{}, nil)
+	m.Stop()
+	m.Stop()
+}
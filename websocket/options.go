@@ -12,6 +12,7 @@ import (
 	"github.com/vaniila/hyper/message"
 	"github.com/vaniila/hyper/router"
 	"github.com/vaniila/hyper/sync"
+	"github.com/vaniila/hyper/websocket/outbound"
 )
 
 // Option func
@@ -42,6 +43,25 @@ type Options struct {
 	// takeover" modes are supported.
 	EnableCompression bool
 
+	// KeepAlive is the interval between ping frames, zero disables pings
+	KeepAlive time.Duration
+
+	// PongTimeout is how long to wait for a pong before dropping the connection
+	PongTimeout time.Duration
+
+	// IdleTimeout closes connections without any incoming message for the
+	// duration, zero keeps idle connections open
+	IdleTimeout time.Duration
+
+	// SendQueueSize is the number of outgoing messages buffered per connection
+	SendQueueSize int
+
+	// OverflowPolicy decides what happens to connections with a full send queue
+	OverflowPolicy outbound.Policy
+
+	// WriteTimeout bounds how long a single outgoing message may take
+	WriteTimeout time.Duration
+
 	// Sync engine server
 	Sync sync.Service
 
@@ -71,7 +91,12 @@ func newID() string {
 
 func newOptions(opts ...Option) Options {
 	opt := Options{
-		ID: newID(),
+		ID:             newID(),
+		KeepAlive:      30 * time.Second,
+		PongTimeout:    10 * time.Second,
+		SendQueueSize:  256,
+		OverflowPolicy: outbound.Disconnect,
+		WriteTimeout:   10 * time.Second,
 	}
 	for _, o := range opts {
 		o(&opt)
@@ -126,6 +151,48 @@ func EnableCompression(b bool) Option {
 	}
 }
 
+// KeepAlive to set the ping interval
+func KeepAlive(d time.Duration) Option {
+	return func(o *Options) {
+		o.KeepAlive = d
+	}
+}
+
+// PongTimeout to set how long to wait for a pong
+func PongTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.PongTimeout = d
+	}
+}
+
+// IdleTimeout to set when idle connections are closed
+func IdleTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.IdleTimeout = d
+	}
+}
+
+// SendQueueSize to set the number of buffered outgoing messages
+func SendQueueSize(i int) Option {
+	return func(o *Options) {
+		o.SendQueueSize = i
+	}
+}
+
+// OverflowPolicy to set what happens when a send queue is full
+func OverflowPolicy(p outbound.Policy) Option {
+	return func(o *Options) {
+		o.OverflowPolicy = p
+	}
+}
+
+// WriteTimeout to set the deadline of outgoing messages
+func WriteTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.WriteTimeout = d
+	}
+}
+
 // Sync to bind sync interface to websocket server
 func Sync(s sync.Service) Option {
 	return func(o *Options) {
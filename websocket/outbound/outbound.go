@@ -0,0 +1,155 @@
+package outbound
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Policy decides what happens when a send queue is full
+type Policy int
+
+// Overflow policies
+const (
+	Disconnect Policy = iota
+	DropOldest
+	DropNewest
+)
+
+// Errors returned by Write
+var (
+	ErrClosed   = errors.New("outbound: connection closed")
+	ErrOverflow = errors.New("outbound: send queue is full")
+)
+
+// Config for outbound queues
+type Config struct {
+
+	// Size is the number of frames buffered per connection, zero is unbounded
+	Size int
+
+	// Policy applied once the queue is full
+	Policy Policy
+
+	// WriteTimeout bounds every write to the connection, zero waits forever
+	WriteTimeout time.Duration
+}
+
+type frame struct {
+	typ  int
+	data []byte
+}
+
+// Queue serializes writes to a websocket connection through a single
+// goroutine so slow clients never block their publishers
+type Queue struct {
+	conn   *websocket.Conn
+	config Config
+	frames []frame
+	notify chan struct{}
+	done   chan struct{}
+	closed bool
+	sync.Mutex
+}
+
+// Start creates the queue and its writer goroutine
+func Start(conn *websocket.Conn, config Config) *Queue {
+	q := &Queue{
+		conn:   conn,
+		config: config,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Write enqueues a frame, it never blocks on the connection
+func (v *Queue) Write(typ int, b []byte) error {
+	v.Lock()
+	if v.closed {
+		v.Unlock()
+		return ErrClosed
+	}
+	if v.config.Size > 0 && len(v.frames) >= v.config.Size {
+		switch v.config.Policy {
+		case DropNewest:
+			v.Unlock()
+			return ErrOverflow
+		case DropOldest:
+			copy(v.frames, v.frames[1:])
+			v.frames = v.frames[:len(v.frames)-1]
+		default:
+			v.Unlock()
+			v.fail()
+			return ErrOverflow
+		}
+	}
+	v.frames = append(v.frames, frame{typ, b})
+	v.Unlock()
+	select {
+	case v.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Len returns the number of frames waiting to be written
+func (v *Queue) Len() int {
+	v.Lock()
+	defer v.Unlock()
+	return len(v.frames)
+}
+
+// Stop ends the writer goroutine, pending frames are discarded
+func (v *Queue) Stop() {
+	v.Lock()
+	defer v.Unlock()
+	if !v.closed {
+		v.closed = true
+		close(v.done)
+	}
+}
+
+// fail stops the queue and closes the connection so its reader terminates
+func (v *Queue) fail() {
+	v.Stop()
+	v.conn.Close()
+}
+
+func (v *Queue) next() (frame, bool) {
+	v.Lock()
+	defer v.Unlock()
+	if v.closed || len(v.frames) == 0 {
+		return frame{}, false
+	}
+	f := v.frames[0]
+	v.frames[0] = frame{}
+	v.frames = v.frames[1:]
+	return f, true
+}
+
+func (v *Queue) run() {
+	for {
+		select {
+		case <-v.done:
+			return
+		case <-v.notify:
+		}
+		for {
+			f, ok := v.next()
+			if !ok {
+				break
+			}
+			if v.config.WriteTimeout > 0 {
+				v.conn.SetWriteDeadline(time.Now().Add(v.config.WriteTimeout))
+			}
+			if err := v.conn.WriteMessage(f.typ, f.data); err != nil {
+				v.fail()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package outbound
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// pair returns the server and client ends of a websocket connection
+func pair(t *testing.T) (*websocket.Conn, *websocket.Conn) {
+	conns := make(chan *websocket.Conn, 1)
+	up := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := up.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conns <- c
+	}))
+	t.Cleanup(srv.Close)
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return <-conns, client
+}
+
+// idle builds a queue without its writer so frames stay queued
+func idle(conn *websocket.Conn, config Config) *Queue {
+	return &Queue{
+		conn:   conn,
+		config: config,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+func TestQueueOverflow(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		writes []string
+		errs   []error
+		queued []string
+		closed bool
+	}{
+		{
+			name:   "unbounded",
+			config: Config{},
+			writes: []string{"a", "b", "c", "d"},
+			errs:   []error{nil, nil, nil, nil},
+			queued: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:   "drop newest",
+			config: Config{Size: 2, Policy: DropNewest},
+			writes: []string{"a", "b", "c", "d"},
+			errs:   []error{nil, nil, ErrOverflow, ErrOverflow},
+			queued: []string{"a", "b"},
+		},
+		{
+			name:   "drop oldest",
+			config: Config{Size: 2, Policy: DropOldest},
+			writes: []string{"a", "b", "c", "d"},
+			errs:   []error{nil, nil, nil, nil},
+			queued: []string{"c", "d"},
+		},
+		{
+			name:   "disconnect",
+			config: Config{Size: 2, Policy: Disconnect},
+			writes: []string{"a", "b", "c", "d"},
+			errs:   []error{nil, nil, ErrOverflow, ErrClosed},
+			closed: true,
+		},
+	}
+	for _, tt := range tests {
+		server, _ := pair(t)
+		q := idle(server, tt.config)
+		for i, s := range tt.writes {
+			if err := q.Write(websocket.TextMessage, []byte(s)); err != tt.errs[i] {
+				t.Errorf("%s: write %q returned %v, want %v", tt.name, s, err, tt.errs[i])
+			}
+		}
+		if q.closed != tt.closed {
+			t.Errorf("%s: closed = %v, want %v", tt.name, q.closed, tt.closed)
+		}
+		if tt.closed {
+			if _, ok := q.next(); ok {
+				t.Errorf("%s: stopped queue still yields frames", tt.name)
+			}
+			continue
+		}
+		var queued []string
+		for f, ok := q.next(); ok; f, ok = q.next() {
+			queued = append(queued, string(f.data))
+		}
+		if strings.Join(queued, ",") != strings.Join(tt.queued, ",") {
+			t.Errorf("%s: queued %v, want %v", tt.name, queued, tt.queued)
+		}
+	}
+}
+
+func TestQueueDisconnectClosesConnection(t *testing.T) {
+	server, client := pair(t)
+	q := idle(server, Config{Size: 1, Policy: Disconnect})
+	q.Write(websocket.TextMessage, []byte("a"))
+	if err := q.Write(websocket.TextMessage, []byte("b")); err != ErrOverflow {
+		t.Fatalf("overflow returned %v, want %v", err, ErrOverflow)
+	}
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	if _, _, err := client.ReadMessage(); err == nil {
+		t.Error("client read succeeded after the server connection was closed")
+	}
+}
+
+func TestQueueWritesInOrder(t *testing.T) {
+	server, client := pair(t)
+	q := Start(server, Config{Size: 16, WriteTimeout: time.Second})
+	defer q.Stop()
+	want := []string{"a", "b", "c", "d", "e"}
+	for _, s := range want {
+		if err := q.Write(websocket.TextMessage, []byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	for _, s := range want {
+		_, b, err := client.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != s {
+			t.Errorf("read %q, want %q", b, s)
+		}
+	}
+}
+
+func TestQueueStop(t *testing.T) {
+	server, _ := pair(t)
+	q := Start(server, Config{})
+	q.Stop()
+	q.Stop()
+	if err := q.Write(websocket.TextMessage, []byte("a")); err != ErrClosed {
+		t.Errorf("write after stop returned %v, want %v", err, ErrClosed)
+	}
+}
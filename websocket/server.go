@@ -35,7 +35,7 @@ func (v *server) Handle(c router.Context) {
 	}
 	defer conn.Close()
 	switch conn.Subprotocol() {
-	case "graphql-ws":
+	case gws.ProtocolGraphQLWS, gws.ProtocolGraphQLTransportWS:
 		if v.gws != nil {
 			v.gws.Handle(c, conn)
 		}
@@ -2,7 +2,10 @@ package websocket
 
 import (
 	"github.com/gorilla/websocket"
+	"github.com/vaniila/hyper/gws"
 	"github.com/vaniila/hyper/router"
+	"github.com/vaniila/hyper/websocket/keepalive"
+	"github.com/vaniila/hyper/websocket/outbound"
 )
 
 // Service interface
@@ -16,6 +19,24 @@ type Service interface {
 // New creates engine server
 func New(opts ...Option) Service {
 	o := newOptions(opts...)
+	alive := keepalive.Config{
+		Interval:    o.KeepAlive,
+		PongTimeout: o.PongTimeout,
+		IdleTimeout: o.IdleTimeout,
+	}
+	out := outbound.Config{
+		Size:         o.SendQueueSize,
+		Policy:       o.OverflowPolicy,
+		WriteTimeout: o.WriteTimeout,
+	}
+	if o.Sync != nil {
+		o.Sync.KeepAlive(alive)
+		o.Sync.Outbound(out)
+	}
+	if o.GQLSubscription != nil {
+		o.GQLSubscription.KeepAlive(alive)
+		o.GQLSubscription.Outbound(out)
+	}
 	s := &server{
 		id:      o.ID,
 		sync:    o.Sync,
@@ -23,7 +44,7 @@ func New(opts ...Option) Service {
 		cache:   o.Cache,
 		message: o.Message,
 		upgrader: websocket.Upgrader{
-			Subprotocols:      []string{"graphql-ws", "hyper-ws"},
+			Subprotocols:      []string{gws.ProtocolGraphQLTransportWS, gws.ProtocolGraphQLWS, "hyper-ws"},
 			HandshakeTimeout:  o.HandshakeTimeout,
 			ReadBufferSize:    o.ReadBufferSize,
 			WriteBufferSize:   o.WriteBufferSize,